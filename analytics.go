@@ -0,0 +1,443 @@
+package av
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	querySymbols      = "SYMBOLS"
+	queryRange        = "RANGE"
+	queryCalculations = "CALCULATIONS"
+	queryWindowSize   = "WINDOW_SIZE"
+
+	// the analytics endpoints expect upper case parameter names, unlike the other endpoints
+	queryAnalyticsInterval = "INTERVAL"
+
+	valueAnalyticsFixedWindowEndpoint   = "ANALYTICS_FIXED_WINDOW"
+	valueAnalyticsSlidingWindowEndpoint = "ANALYTICS_SLIDING_WINDOW"
+
+	// minWindowSize is the smallest window size accepted by the sliding window analytics
+	minWindowSize = 10
+
+	// analyticsDateFormat is the format of explicit dates in an AnalyticsRange
+	analyticsDateFormat = "2006-01-02"
+	// analyticsIndexKey is the key of the symbols of a matrix calculation, i.e. CORRELATION
+	analyticsIndexKey = "index"
+)
+
+var (
+	// ErrInvalidAnalyticsParams is returned when analytics are queried without symbols, calculations or range
+	ErrInvalidAnalyticsParams = errors.New("invalid analytics parameters")
+	// ErrInvalidAnalyticsInterval is returned when analytics are queried with an unsupported interval
+	ErrInvalidAnalyticsInterval = errors.New("invalid analytics interval")
+	// ErrInvalidCalculation is returned when a calculation is not one of the Calculation* package constants
+	ErrInvalidCalculation = errors.New("invalid analytics calculation")
+	// ErrInvalidWindowSize is returned when sliding window analytics are queried with a window smaller than 10
+	ErrInvalidWindowSize = errors.New("analytics window size must be at least 10")
+)
+
+// AnalyticsInterval* are the intervals between the prices analytics are calculated from
+const (
+	AnalyticsIntervalOneMinute     = "1min"
+	AnalyticsIntervalFiveMinute    = "5min"
+	AnalyticsIntervalFifteenMinute = "15min"
+	AnalyticsIntervalThirtyMinute  = "30min"
+	AnalyticsIntervalSixtyMinute   = "60min"
+	AnalyticsIntervalDaily         = "DAILY"
+	AnalyticsIntervalWeekly        = "WEEKLY"
+	AnalyticsIntervalMonthly       = "MONTHLY"
+)
+
+// validAnalyticsInterval reports whether interval is accepted by the analytics endpoints
+func validAnalyticsInterval(interval string) bool {
+	switch interval {
+	case AnalyticsIntervalOneMinute, AnalyticsIntervalFiveMinute, AnalyticsIntervalFifteenMinute,
+		AnalyticsIntervalThirtyMinute, AnalyticsIntervalSixtyMinute,
+		AnalyticsIntervalDaily, AnalyticsIntervalWeekly, AnalyticsIntervalMonthly:
+		return true
+	}
+	return false
+}
+
+// AnalyticsRange is the time range analytics are calculated over.
+// Use NewAnalyticsRange for named ranges and NewAnalyticsDateRange for explicit dates.
+type AnalyticsRange struct {
+	values []string
+}
+
+// AnalyticsRangeFull calculates analytics over the full history of the symbols
+var AnalyticsRangeFull = NewAnalyticsRange("full")
+
+// NewAnalyticsRange creates a named range, i.e. "full", "1month" or "5year"
+func NewAnalyticsRange(name string) AnalyticsRange {
+	return AnalyticsRange{values: []string{name}}
+}
+
+// NewAnalyticsDateRange creates a range between two dates, both inclusive
+func NewAnalyticsDateRange(from, to time.Time) AnalyticsRange {
+	return AnalyticsRange{values: []string{from.Format(analyticsDateFormat), to.Format(analyticsDateFormat)}}
+}
+
+// Calculation specifies a statistic calculated by the analytics endpoints.
+// For valid options, see the Calculation* package constants.
+type Calculation uint8
+
+const (
+	CalculationMean Calculation = iota
+	CalculationMedian
+	CalculationCumulativeReturn
+	CalculationVariance
+	CalculationStdDev
+	CalculationMaxDrawdown
+	CalculationHistogram
+	CalculationAutocorrelation
+	CalculationCovariance
+	CalculationCorrelation
+)
+
+func (c Calculation) String() string {
+	switch c {
+	case CalculationMean:
+		return "CalculationMean"
+	case CalculationMedian:
+		return "CalculationMedian"
+	case CalculationCumulativeReturn:
+		return "CalculationCumulativeReturn"
+	case CalculationVariance:
+		return "CalculationVariance"
+	case CalculationStdDev:
+		return "CalculationStdDev"
+	case CalculationMaxDrawdown:
+		return "CalculationMaxDrawdown"
+	case CalculationHistogram:
+		return "CalculationHistogram"
+	case CalculationAutocorrelation:
+		return "CalculationAutocorrelation"
+	case CalculationCovariance:
+		return "CalculationCovariance"
+	case CalculationCorrelation:
+		return "CalculationCorrelation"
+	}
+	return "CalculationUnknown"
+}
+
+// keyName returns the name of the Calculation used for Alpha Vantage API
+func (c Calculation) keyName() string {
+	switch c {
+	case CalculationMean:
+		return "MEAN"
+	case CalculationMedian:
+		return "MEDIAN"
+	case CalculationCumulativeReturn:
+		return "CUMULATIVE_RETURN"
+	case CalculationVariance:
+		return "VARIANCE"
+	case CalculationStdDev:
+		return "STDDEV"
+	case CalculationMaxDrawdown:
+		return "MAX_DRAWDOWN"
+	case CalculationHistogram:
+		return "HISTOGRAM"
+	case CalculationAutocorrelation:
+		return "AUTOCORRELATION"
+	case CalculationCovariance:
+		return "COVARIANCE"
+	case CalculationCorrelation:
+		return "CORRELATION"
+	}
+	return "UNKNOWN"
+}
+
+// AnalyticsResult holds the results of a fixed window analytics query
+type AnalyticsResult struct {
+	Symbols  []string
+	MinDate  string
+	MaxDate  string
+	OHLC     string
+	Interval string
+
+	// calculations are the raw results keyed by the names returned by Alpha Vantage, i.e. "MEAN"
+	calculations map[string]AnalyticsValue
+}
+
+// Calculation returns the result of a calculation.
+// The boolean is false if the calculation was not returned.
+func (r *AnalyticsResult) Calculation(calc Calculation) (AnalyticsValue, bool) {
+	v, ok := r.calculations[calc.keyName()]
+	return v, ok
+}
+
+// BySymbol returns the result of a calculation with a single value per symbol, i.e. CalculationMean
+func (r *AnalyticsResult) BySymbol(calc Calculation) (map[string]float64, error) {
+	v, ok := r.Calculation(calc)
+	if !ok {
+		return nil, errors.Errorf("missing calculation %s", calc.keyName())
+	}
+	return v.BySymbol()
+}
+
+// Matrix returns the result of a calculation between each pair of symbols, i.e. CalculationCorrelation
+func (r *AnalyticsResult) Matrix(calc Calculation) (*AnalyticsMatrix, error) {
+	v, ok := r.Calculation(calc)
+	if !ok {
+		return nil, errors.Errorf("missing calculation %s", calc.keyName())
+	}
+	return v.Matrix()
+}
+
+// AnalyticsMatrix is the result of a calculation between each pair of symbols.
+// Alpha Vantage only returns the lower triangle, use At to read any pair.
+type AnalyticsMatrix struct {
+	Symbols []string
+	Values  [][]float64
+}
+
+// At returns the value between the symbols at index i and j
+func (m *AnalyticsMatrix) At(i, j int) float64 {
+	if j > i {
+		i, j = j, i
+	}
+	if i >= len(m.Values) || j >= len(m.Values[i]) {
+		return 0
+	}
+	return m.Values[i][j]
+}
+
+// AnalyticsValue is the result of a single calculation.
+// Its shape depends on the calculation, so it is decoded on demand.
+type AnalyticsValue json.RawMessage
+
+// UnmarshalJSON keeps the raw calculation result, see Decode
+func (v *AnalyticsValue) UnmarshalJSON(data []byte) error {
+	*v = append((*v)[:0], data...)
+	return nil
+}
+
+// Decode decodes the calculation result into out, like json.Unmarshal
+func (v AnalyticsValue) Decode(out interface{}) error {
+	return json.Unmarshal(v, out)
+}
+
+// BySymbol decodes a calculation with a single value per symbol, i.e. CalculationMean
+func (v AnalyticsValue) BySymbol() (map[string]float64, error) {
+	var values map[string]float64
+	if err := v.Decode(&values); err != nil {
+		return nil, errors.Wrap(err, "error decoding analytics value by symbol")
+	}
+	return values, nil
+}
+
+// Matrix decodes a calculation between each pair of symbols, i.e. CalculationCorrelation.
+// The matrix is keyed by the lower case calculation name next to the symbol index.
+func (v AnalyticsValue) Matrix() (*AnalyticsMatrix, error) {
+	var data map[string]json.RawMessage
+	if err := v.Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding analytics matrix")
+	}
+	matrix := &AnalyticsMatrix{}
+	for key, raw := range data {
+		var err error
+		if key == analyticsIndexKey {
+			err = json.Unmarshal(raw, &matrix.Symbols)
+		} else {
+			err = json.Unmarshal(raw, &matrix.Values)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding analytics matrix %s", key)
+		}
+	}
+	return matrix, nil
+}
+
+// AnalyticsWindowResult holds the results of a sliding window analytics query
+type AnalyticsWindowResult struct {
+	Symbols    []string
+	MinDate    string
+	MaxDate    string
+	OHLC       string
+	Interval   string
+	WindowSize int
+
+	// calculations are the raw results keyed by the names returned by Alpha Vantage, i.e. "MEAN"
+	calculations map[string]AnalyticsValue
+}
+
+// Calculation returns the result of a calculation.
+// The boolean is false if the calculation was not returned.
+func (r *AnalyticsWindowResult) Calculation(calc Calculation) (AnalyticsValue, bool) {
+	v, ok := r.calculations[calc.keyName()]
+	return v, ok
+}
+
+// Series returns the running values of a calculation per symbol, i.e. CalculationMean.
+// Values are returned from past to present.
+func (r *AnalyticsWindowResult) Series(calc Calculation) (map[string][]*DateValue, error) {
+	v, ok := r.Calculation(calc)
+	if !ok {
+		return nil, errors.Errorf("missing calculation %s", calc.keyName())
+	}
+	return v.Series()
+}
+
+// Series decodes the running values of a sliding window calculation per symbol.
+// Alpha Vantage nests them under a running key, i.e. "RUNNING_MEAN", then by symbol and date.
+func (v AnalyticsValue) Series() (map[string][]*DateValue, error) {
+	var running map[string]map[string]map[string]float64
+	if err := v.Decode(&running); err != nil {
+		return nil, errors.Wrap(err, "error decoding analytics series")
+	}
+
+	series := make(map[string][]*DateValue)
+	for _, symbols := range running {
+		for symbol, dates := range symbols {
+			values := make([]*DateValue, 0, len(dates))
+			for date, f := range dates {
+				d, err := parseDate(date, indicatorDateFormats...)
+				if err != nil {
+					return nil, errors.Wrapf(err, "error parsing analytics date %s", date)
+				}
+				values = append(values, &DateValue{Time: d, Value: f})
+			}
+			sort.Sort(sortDateValuesByDate(values))
+			series[symbol] = values
+		}
+	}
+	return series, nil
+}
+
+// analyticsMetaData is the json meta data of an analytics response
+type analyticsMetaData struct {
+	Symbols    string `json:"symbols"`
+	MinDate    string `json:"min_dt"`
+	MaxDate    string `json:"max_dt"`
+	OHLC       string `json:"ohlc"`
+	Interval   string `json:"interval"`
+	WindowSize int    `json:"window_size"`
+}
+
+// analyticsData is the json body of an analytics response
+type analyticsData struct {
+	MetaData analyticsMetaData `json:"meta_data"`
+	Payload  struct {
+		Calculations map[string]AnalyticsValue `json:"RETURNS_CALCULATIONS"`
+	} `json:"payload"`
+}
+
+// symbols returns the symbols of the meta data
+func (d analyticsMetaData) symbols() []string {
+	if d.Symbols == "" {
+		return nil
+	}
+	return strings.Split(d.Symbols, ",")
+}
+
+// parseAnalyticsData will parse json data from a reader
+func parseAnalyticsData(r io.Reader) (*AnalyticsResult, error) {
+	var data analyticsData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding analytics")
+	}
+	d := data.MetaData
+
+	return &AnalyticsResult{
+		Symbols:      d.symbols(),
+		MinDate:      d.MinDate,
+		MaxDate:      d.MaxDate,
+		OHLC:         d.OHLC,
+		Interval:     d.Interval,
+		calculations: data.Payload.Calculations,
+	}, nil
+}
+
+// parseAnalyticsWindowData will parse json data of a sliding window from a reader
+func parseAnalyticsWindowData(r io.Reader) (*AnalyticsWindowResult, error) {
+	var data analyticsData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding analytics")
+	}
+	d := data.MetaData
+
+	return &AnalyticsWindowResult{
+		Symbols:      d.symbols(),
+		MinDate:      d.MinDate,
+		MaxDate:      d.MaxDate,
+		OHLC:         d.OHLC,
+		Interval:     d.Interval,
+		WindowSize:   d.WindowSize,
+		calculations: data.Payload.Calculations,
+	}, nil
+}
+
+// analyticsRequest queries an analytics endpoint after validating the common parameters
+func (c *Client) analyticsRequest(ctx context.Context, function string, symbols []string, rng AnalyticsRange, interval string, calcs []Calculation, params map[string]string) (io.ReadCloser, error) {
+	if len(symbols) == 0 || len(calcs) == 0 || len(rng.values) == 0 {
+		return nil, ErrInvalidAnalyticsParams
+	}
+	if !validAnalyticsInterval(interval) {
+		return nil, ErrInvalidAnalyticsInterval
+	}
+	names := make([]string, len(calcs))
+	for i, calc := range calcs {
+		if calc > CalculationCorrelation {
+			return nil, ErrInvalidCalculation
+		}
+		names[i] = calc.keyName()
+	}
+
+	query := map[string]string{
+		queryEndpoint:          function,
+		querySymbols:           strings.Join(symbols, ","),
+		queryAnalyticsInterval: interval,
+		queryCalculations:      strings.Join(names, ","),
+		queryDataType:          valueDataTypeJson,
+	}
+	for key, value := range params {
+		query[key] = value
+	}
+	endpoint := c.buildRequestPath(query)
+
+	// explicit date ranges repeat the range parameter
+	values := endpoint.Query()
+	values[queryRange] = rng.values
+	endpoint.RawQuery = values.Encode()
+
+	return c.request(ctx, endpoint)
+}
+
+// AnalyticsFixedWindow queries statistics of a set of symbols over a fixed time window.
+// The interval is one of the AnalyticsInterval* package constants.
+// This endpoint requires a premium API key.
+func (c *Client) AnalyticsFixedWindow(ctx context.Context, symbols []string, rng AnalyticsRange, interval string, calcs []Calculation) (*AnalyticsResult, error) {
+	body, err := c.analyticsRequest(ctx, valueAnalyticsFixedWindowEndpoint, symbols, rng, interval, calcs, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseAnalyticsData(body)
+}
+
+// AnalyticsSlidingWindow queries statistics of a set of symbols over a window sliding through
+// the range, one result per date. The interval is one of the AnalyticsInterval* package constants.
+// ErrInvalidWindowSize is returned if windowSize is smaller than 10.
+// This endpoint requires a premium API key.
+func (c *Client) AnalyticsSlidingWindow(ctx context.Context, symbols []string, rng AnalyticsRange, interval string, windowSize int, calcs []Calculation) (*AnalyticsWindowResult, error) {
+	if windowSize < minWindowSize {
+		return nil, ErrInvalidWindowSize
+	}
+	body, err := c.analyticsRequest(ctx, valueAnalyticsSlidingWindowEndpoint, symbols, rng, interval, calcs, map[string]string{
+		queryWindowSize: strconv.Itoa(windowSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseAnalyticsWindowData(body)
+}
@@ -0,0 +1,197 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_AnalyticsFixedWindow(t *testing.T) {
+	tests := []struct {
+		desc     string
+		rng      AnalyticsRange
+		expected string
+	}{
+		{
+			desc:     "named range",
+			rng:      NewAnalyticsRange("1month"),
+			expected: "query?CALCULATIONS=MEAN%2CCORRELATION&INTERVAL=DAILY&RANGE=1month&SYMBOLS=AAPL%2CMSFT%2CIBM&apikey=test&datatype=json&function=ANALYTICS_FIXED_WINDOW&outputsize=compact",
+		},
+		{
+			desc:     "date range",
+			rng:      NewAnalyticsDateRange(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC)),
+			expected: "query?CALCULATIONS=MEAN%2CCORRELATION&INTERVAL=DAILY&RANGE=2023-07-01&RANGE=2023-08-31&SYMBOLS=AAPL%2CMSFT%2CIBM&apikey=test&datatype=json&function=ANALYTICS_FIXED_WINDOW&outputsize=compact",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleAnalyticsData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, err := client.AnalyticsFixedWindow(context.Background(), []string{"AAPL", "MSFT", "IBM"}, tt.rng, AnalyticsIntervalDaily,
+				[]Calculation{CalculationMean, CalculationCorrelation})
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestParseAnalyticsData(t *testing.T) {
+	result, err := parseAnalyticsData(NewBuffCloser(sampleAnalyticsData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if !reflect.DeepEqual(result.Symbols, []string{"AAPL", "MSFT", "IBM"}) || result.MinDate != "2023-07-03" {
+		t.Errorf("unexpected meta data, got %+v", result)
+	}
+
+	mean, err := result.BySymbol(CalculationMean)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if mean["AAPL"] != 0.0012 || mean["MSFT"] != -0.0004 || mean["IBM"] != 0.0003 {
+		t.Errorf("unexpected mean, got %v", mean)
+	}
+
+	correlation, err := result.Matrix(CalculationCorrelation)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if !reflect.DeepEqual(correlation.Symbols, []string{"AAPL", "MSFT", "IBM"}) {
+		t.Errorf("unexpected correlation symbols, got %v", correlation.Symbols)
+	}
+	if v := correlation.At(2, 1); v != 0.3318 {
+		t.Errorf("unexpected correlation, want 0.3318 got %f", v)
+	}
+	if v := correlation.At(1, 2); v != 0.3318 {
+		t.Errorf("unexpected mirrored correlation, want 0.3318 got %f", v)
+	}
+
+	if _, ok := result.Calculation(CalculationStdDev); ok {
+		t.Error("unexpected calculation STDDEV")
+	}
+}
+
+func TestClient_AnalyticsFixedWindow_invalidParams(t *testing.T) {
+	conn := NewResponseConnection(&http.Response{})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	tests := []struct {
+		desc     string
+		symbols  []string
+		rng      AnalyticsRange
+		interval string
+		calcs    []Calculation
+		expected error
+	}{
+		{
+			desc:     "no symbols",
+			rng:      AnalyticsRangeFull,
+			interval: AnalyticsIntervalDaily,
+			calcs:    []Calculation{CalculationMean},
+			expected: ErrInvalidAnalyticsParams,
+		},
+		{
+			desc:     "no range",
+			symbols:  []string{"AAPL"},
+			interval: AnalyticsIntervalDaily,
+			calcs:    []Calculation{CalculationMean},
+			expected: ErrInvalidAnalyticsParams,
+		},
+		{
+			desc:     "invalid interval",
+			symbols:  []string{"AAPL"},
+			rng:      AnalyticsRangeFull,
+			interval: "daily",
+			calcs:    []Calculation{CalculationMean},
+			expected: ErrInvalidAnalyticsInterval,
+		},
+		{
+			desc:     "invalid calculation",
+			symbols:  []string{"AAPL"},
+			rng:      AnalyticsRangeFull,
+			interval: AnalyticsIntervalDaily,
+			calcs:    []Calculation{CalculationCorrelation + 1},
+			expected: ErrInvalidCalculation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := client.AnalyticsFixedWindow(context.Background(), tt.symbols, tt.rng, tt.interval, tt.calcs); err != tt.expected {
+				t.Errorf("unexpected error, want %v got %v", tt.expected, err)
+			}
+			if conn.endpoint != nil {
+				t.Errorf("request was made to %s", conn.endpoint)
+			}
+		})
+	}
+}
+
+func TestClient_AnalyticsSlidingWindow(t *testing.T) {
+	const (
+		expected = "query?CALCULATIONS=MEAN%2CSTDDEV&INTERVAL=DAILY&RANGE=2month&SYMBOLS=AAPL%2CIBM&WINDOW_SIZE=10&apikey=test&datatype=json&function=ANALYTICS_SLIDING_WINDOW&outputsize=compact"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleAnalyticsWindowData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.AnalyticsSlidingWindow(context.Background(), []string{"AAPL", "IBM"}, NewAnalyticsRange("2month"), AnalyticsIntervalDaily, 10,
+		[]Calculation{CalculationMean, CalculationStdDev})
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if result.WindowSize != 10 || !reflect.DeepEqual(result.Symbols, []string{"AAPL", "IBM"}) {
+		t.Errorf("unexpected meta data, got %+v", result)
+	}
+
+	mean, err := result.Series(CalculationMean)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	expectedMean := []*DateValue{
+		{Time: time.Date(2023, 7, 18, 0, 0, 0, 0, time.UTC), Value: 0.0015},
+		{Time: time.Date(2023, 7, 19, 0, 0, 0, 0, time.UTC), Value: 0.0021},
+	}
+	if !reflect.DeepEqual(mean["AAPL"], expectedMean) {
+		t.Errorf("unexpected AAPL mean, want %v got %v", expectedMean, mean["AAPL"])
+	}
+
+	stddev, err := result.Series(CalculationStdDev)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(stddev["IBM"]) != 2 || stddev["IBM"][1].Value != 0.0087 {
+		t.Errorf("unexpected IBM stddev, got %v", stddev["IBM"])
+	}
+}
+
+func TestClient_AnalyticsSlidingWindow_invalidWindowSize(t *testing.T) {
+	conn := NewResponseConnection(&http.Response{})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	_, err := client.AnalyticsSlidingWindow(context.Background(), []string{"AAPL"}, AnalyticsRangeFull, AnalyticsIntervalDaily, 9, []Calculation{CalculationMean})
+	if err != ErrInvalidWindowSize {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidWindowSize, err)
+	}
+	if conn.endpoint != nil {
+		t.Errorf("request was made to %s", conn.endpoint)
+	}
+}
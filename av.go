@@ -2,43 +2,76 @@ package av
 
 import (
 	"context"
+	"io"
 	"net/url"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
+// ErrReservedQueryParam is the cause of the panic of NewClient given WithDefaultQueryParams
+// with the api key or function parameter
+var ErrReservedQueryParam = errors.New("api key and function query parameters cannot be defaulted")
+
 const (
 	// HostDefault is the default host for Alpha Vantage
 	HostDefault    = "www.alphavantage.co"
 	TimeoutDefault = time.Second * 30
+	// UserAgentDefault is the default User-Agent of requests, see WithUserAgent
+	UserAgentDefault = "go-alpha-vantage"
+	// PingTimeout is the longest a Ping waits for Alpha Vantage
+	PingTimeout = time.Second * 5
 )
 
 const (
 	schemeHttps = "https"
 
-	queryApiKey     = "apikey"
-	queryDataType   = "datatype"
-	queryOutputSize = "outputsize"
-	querySymbol     = "symbol"
-	queryMarket     = "market"
-	queryEndpoint   = "function"
-	queryInterval   = "interval"
+	queryApiKey        = "apikey"
+	queryDataType      = "datatype"
+	queryOutputSize    = "outputsize"
+	querySymbol        = "symbol"
+	queryMarket        = "market"
+	queryEndpoint      = "function"
+	queryInterval      = "interval"
+	queryMonth         = "month"
+	queryExtendedHours = "extended_hours"
+	queryAdjusted      = "adjusted"
 
 	valueCompact                 = "compact"
-	valueJson                    = "csv"
+	valueFull                    = "full"
+	valueDataTypeCsv             = "csv"
+	valueDataTypeJson            = "json"
 	valueDigitalCurrencyEndpoint = "DIGITAL_CURRENCY_INTRADAY"
+	valueAllCommoditiesEndpoint  = "ALL_COMMODITIES"
+	valueGlobalQuoteEndpoint     = "GLOBAL_QUOTE"
+
+	// pingSymbol is the liquid symbol quoted by Ping
+	pingSymbol = "IBM"
 
 	pathQuery = "query"
 )
 
+// clientNameKey is the context key of the name of the client making a request
+type clientNameKey struct{}
+
+// ClientNameFromContext returns the name of the client making a request, set with WithClientName.
+// The boolean is false if the client has no name.
+func ClientNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(clientNameKey{}).(string)
+	return name, ok
+}
+
 // Client is a service used to query Alpha Vantage stock data
 type Client struct {
 	copts clientOptions
+
+	// ownsConn is set if the connection was created by the client rather than given
+	ownsConn bool
 }
 
 func defaultClientOptions() clientOptions {
 	return clientOptions{
 		apiKey: "",
-		conn:   NewConnection(),
 	}
 }
 
@@ -52,6 +85,12 @@ func NewClient(opts ...ClientOption) *Client {
 		opt.apply(&c.copts)
 	}
 
+	// the default connection is only created when none is given, as it runs a rate limiter
+	if c.copts.conn == nil {
+		c.copts.conn = NewConnection()
+		c.ownsConn = true
+	}
+
 	return c
 }
 
@@ -59,6 +98,39 @@ func (c *Client) Conn() Connection {
 	return c.copts.conn
 }
 
+// Close releases the resources of the client.
+// A connection given with WithConnection is not closed, as it may be shared.
+// Connections created with NewConnection implement io.Closer and can be closed separately.
+func (c *Client) Close() error {
+	if !c.ownsConn {
+		return nil
+	}
+	if closer, ok := c.Conn().(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Ping checks that Alpha Vantage can be reached and accepts the API key of the client,
+// i.e. for a readiness probe. It queries a single quote, which counts towards the quota
+// like any other request, and gives up after PingTimeout.
+// An *APIError is returned for an invalid API key or, if Throttled, when rate limited.
+// Other errors are network errors or ErrDailyLimitReached.
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, PingTimeout)
+	defer cancel()
+
+	endpoint := c.buildRequestPath(map[string]string{
+		queryEndpoint: valueGlobalQuoteEndpoint,
+		querySymbol:   pingSymbol,
+	})
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
 // buildRequestPath builds an endpoint URL with the given query parameters
 func (c *Client) buildRequestPath(params map[string]string) *url.URL {
 	// build our URL
@@ -67,9 +139,17 @@ func (c *Client) buildRequestPath(params map[string]string) *url.URL {
 
 	// base parameters
 	query := endpoint.Query()
+	query.Set(queryDataType, valueDataTypeCsv)
+	query.Set(queryOutputSize, c.copts.outputSize.keyName())
+	if entitlement := c.copts.entitlement.keyName(); entitlement != "" {
+		query.Set(queryEntitlement, entitlement)
+	}
+
+	// client parameters, the api key is always kept
+	for key, value := range c.copts.queryParams {
+		query.Set(key, value)
+	}
 	query.Set(queryApiKey, c.copts.apiKey)
-	query.Set(queryDataType, valueJson)
-	query.Set(queryOutputSize, valueCompact)
 
 	// additional parameters
 	for key, value := range params {
@@ -81,49 +161,228 @@ func (c *Client) buildRequestPath(params map[string]string) *url.URL {
 	return endpoint
 }
 
+// request queries the endpoint and returns the response body.
+// An *APIError is returned if Alpha Vantage responded with a message instead of data.
+func (c *Client) request(ctx context.Context, endpoint *url.URL) (io.ReadCloser, error) {
+	if c.copts.name != "" {
+		ctx = context.WithValue(ctx, clientNameKey{}, c.copts.name)
+	}
+	// the default timeout is cancelled once the body is closed, as it covers reading it
+	cancel := func() {}
+	if _, ok := ctx.Deadline(); !ok && c.copts.callTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.copts.callTimeout)
+	}
+	response, err := c.Conn().Request(ctx, endpoint)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	body, err := checkAPIError(response.Body)
+	if err != nil {
+		response.Body.Close()
+		cancel()
+		return nil, err
+	}
+	return &releaseReadCloser{ReadCloser: body, release: cancel}, nil
+}
+
+// parseTimeSeries parses and sorts the time series of a response body, as csv or json.
+// ErrNoData is returned for a response without values, unless WithAllowEmpty was given.
+func (c *Client) parseTimeSeries(body io.Reader, o *requestOptions) ([]*TimeSeriesValue, error) {
+	var (
+		values []*TimeSeriesValue
+		err    error
+	)
+	if o.isJSON() {
+		values, err = parseTimeSeriesJSON(body, o.metadata)
+	} else {
+		values, err = parseTimeSeriesData(body)
+	}
+	if err == ErrNoData && o.allowEmpty {
+		return []*TimeSeriesValue{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sortTimeSeriesValues(values, c.copts.sortOrder), nil
+}
+
 // StockTimeSeriesIntraday queries a stock symbols statistics throughout the day.
-// Data is returned from past to present.
-func (c *Client) StockTimeSeriesIntraday(ctx context.Context, timeInterval TimeInterval, symbol string) ([]*TimeSeriesValue, error) {
-	endpoint := c.buildRequestPath(map[string]string{
+// The query can be changed with RequestOptions, i.e. WithCallOutputSize.
+// A past month queried with WithIntradayMonth always has the full output size.
+// ErrNoData is returned if Alpha Vantage responds without values, unless WithAllowEmpty is given.
+// Data is returned from past to present, unless configured otherwise with WithSortOrder.
+func (c *Client) StockTimeSeriesIntraday(ctx context.Context, timeInterval TimeInterval, symbol string, opts ...RequestOption) ([]*TimeSeriesValue, error) {
+	if !timeInterval.isIntraday() {
+		return nil, ErrInvalidInterval
+	}
+	o, err := newRequestOptions(map[string]string{
 		queryEndpoint: timeSeriesIntraday.keyName(),
 		queryInterval: timeInterval.keyName(),
 		querySymbol:   symbol,
-	})
-	response, err := c.Conn().Request(ctx, endpoint)
+	}, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	return parseTimeSeriesData(response.Body)
+	endpoint := c.buildRequestPath(o.params)
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return c.parseTimeSeries(body, o)
 }
 
 // StockTimeSeries queries a stock symbols statistics for a given time frame.
-// Data is returned from past to present.
-func (c *Client) StockTimeSeries(ctx context.Context, timeSeries TimeSeries, symbol string) ([]*TimeSeriesValue, error) {
-	endpoint := c.buildRequestPath(map[string]string{
+// The query can be changed with RequestOptions, i.e. WithCallOutputSize.
+// ErrNoData is returned if Alpha Vantage responds without values, unless WithAllowEmpty is given.
+// Data is returned from past to present, unless configured otherwise with WithSortOrder.
+func (c *Client) StockTimeSeries(ctx context.Context, timeSeries TimeSeries, symbol string, opts ...RequestOption) ([]*TimeSeriesValue, error) {
+	if !timeSeries.IsValid() {
+		return nil, ErrInvalidSeries
+	}
+	o, err := newRequestOptions(map[string]string{
 		queryEndpoint: timeSeries.keyName(),
 		querySymbol:   symbol,
-	})
-	response, err := c.Conn().Request(ctx, endpoint)
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.buildRequestPath(o.params)
+	body, err := c.request(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	return parseTimeSeriesData(response.Body)
+	defer body.Close()
+	return c.parseTimeSeries(body, o)
+}
+
+// StockTimeSeriesIntradayFull queries a stock symbols statistics throughout the day,
+// with the full output size rather than the latest 100 values.
+// It is a shortcut for StockTimeSeriesIntraday with WithCallOutputSize(OutputSizeFull).
+func (c *Client) StockTimeSeriesIntradayFull(ctx context.Context, timeInterval TimeInterval, symbol string) ([]*TimeSeriesValue, error) {
+	return c.StockTimeSeriesIntraday(ctx, timeInterval, symbol, WithCallOutputSize(OutputSizeFull))
+}
+
+// StockTimeSeriesFull queries a stock symbols statistics for a given time frame,
+// with the full output size rather than the latest 100 values, i.e. 20+ years of daily values.
+// It is a shortcut for StockTimeSeries with WithCallOutputSize(OutputSizeFull).
+func (c *Client) StockTimeSeriesFull(ctx context.Context, timeSeries TimeSeries, symbol string) ([]*TimeSeriesValue, error) {
+	return c.StockTimeSeries(ctx, timeSeries, symbol, WithCallOutputSize(OutputSizeFull))
+}
+
+// StockTimeSeriesRange queries a stock symbols statistics for a given time frame,
+// keeping only the values within the inclusive range from to.
+// The full output size is queried when the compact output would not reach back to from,
+// see OutputSizeSince. The decision can be overridden with WithCallOutputSize.
+// ErrNoData is returned if Alpha Vantage responds without values, but not if none are within the range.
+// Data is returned from past to present, unless configured otherwise with WithSortOrder.
+func (c *Client) StockTimeSeriesRange(ctx context.Context, timeSeries TimeSeries, symbol string, from, to time.Time, opts ...RequestOption) ([]*TimeSeriesValue, error) {
+	if !timeSeries.IsValid() {
+		return nil, ErrInvalidSeries
+	}
+	o, err := newRequestOptions(map[string]string{
+		queryEndpoint:   timeSeries.keyName(),
+		querySymbol:     symbol,
+		queryOutputSize: OutputSizeSince(timeSeries, from, time.Now()).keyName(),
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.buildRequestPath(o.params)
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	values, err := c.parseTimeSeries(body, o)
+	if err != nil {
+		return nil, err
+	}
+	return filterTimeSeriesValues(values, from, to), nil
+}
+
+// StockTimeSeriesStream queries a stock symbols statistics for a given time frame.
+// Values are read one at a time from the response, see TimeSeriesIterator.
+// The iterator must be closed if it is not read until the end.
+// The query can be changed with RequestOptions, i.e. WithCallOutputSize, except WithJSON.
+func (c *Client) StockTimeSeriesStream(ctx context.Context, timeSeries TimeSeries, symbol string, opts ...RequestOption) (*TimeSeriesIterator, error) {
+	if !timeSeries.IsValid() {
+		return nil, ErrInvalidSeries
+	}
+	o, err := newRequestOptions(map[string]string{
+		queryEndpoint: timeSeries.keyName(),
+		querySymbol:   symbol,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if o.isJSON() {
+		return nil, ErrStreamJSON
+	}
+	endpoint := c.buildRequestPath(o.params)
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return newTimeSeriesIterator(body)
 }
 
 // DigitalCurrency queries statistics of a digital currency in terms of a physical currency throughout the day.
+// The query can be changed with RequestOptions, i.e. WithFullOutput.
 // Data is returned from past to present.
-func (c *Client) DigitalCurrency(ctx context.Context, digital string, physical string) ([]*DigitalCurrencySeriesValue, error) {
-	endpoint := c.buildRequestPath(map[string]string{
+func (c *Client) DigitalCurrency(ctx context.Context, digital string, physical string, opts ...RequestOption) ([]*DigitalCurrencySeriesValue, error) {
+	o, err := newRequestOptions(map[string]string{
 		queryEndpoint: valueDigitalCurrencyEndpoint,
 		querySymbol:   digital,
 		queryMarket:   physical,
-	})
-	response, err := c.Conn().Request(ctx, endpoint)
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.buildRequestPath(o.params)
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseDigitalCurrencySeriesData(body)
+}
+
+// Commodity queries the global price of a commodity.
+// The interval is one of the CommodityInterval* package constants.
+// ErrInvalidCommodity is returned if commodity is not one of the Commodity* package constants.
+// Data is returned from past to present.
+func (c *Client) Commodity(ctx context.Context, commodity Commodity, interval string) ([]*DateValue, error) {
+	if commodity > CommodityCoffee {
+		return nil, ErrInvalidCommodity
+	}
+	return c.commodityIndex(ctx, commodity.keyName(), interval)
+}
+
+// GlobalCommoditiesIndex queries the global price index of all commodities.
+// The interval is one of the CommodityInterval* package constants.
+// Data is returned from past to present.
+func (c *Client) GlobalCommoditiesIndex(ctx context.Context, interval string) ([]*DateValue, error) {
+	return c.commodityIndex(ctx, valueAllCommoditiesEndpoint, interval)
+}
+
+// commodityIndex queries a commodity price endpoint at the given interval
+func (c *Client) commodityIndex(ctx context.Context, function string, interval string) ([]*DateValue, error) {
+	if !validCommodityInterval(interval) {
+		return nil, ErrInvalidCommodityInterval
+	}
+	params := map[string]string{
+		queryEndpoint: function,
+	}
+	if interval != "" {
+		params[queryInterval] = interval
+	}
+	endpoint := c.buildRequestPath(params)
+	body, err := c.request(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	return parseDigitalCurrencySeriesData(response.Body)
+	defer body.Close()
+	return parseDateValueData(body)
 }
@@ -2,8 +2,14 @@ package av
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"net/url"
+	"runtime"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestNewClient(t *testing.T) {
@@ -30,6 +36,343 @@ func TestClient_StockTimeSeries_buildsUrl(t *testing.T) {
 	}
 }
 
+func TestClient_StockTimeSeries_callOutputSize(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []RequestOption
+		expected string
+	}{
+		{
+			desc:     "default",
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "full",
+			opts:     []RequestOption{WithCallOutputSize(OutputSizeFull)},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=full&symbol=TEST",
+		},
+		{
+			desc:     "compact",
+			opts:     []RequestOption{WithCallOutputSize(OutputSizeCompact)},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, _ = client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_StockTimeSeries_outputSize(t *testing.T) {
+	tests := []struct {
+		desc     string
+		copts    []ClientOption
+		opts     []RequestOption
+		expected string
+	}{
+		{
+			desc:     "default",
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "full client",
+			copts:    []ClientOption{WithOutputSize(OutputSizeFull)},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=full&symbol=TEST",
+		},
+		{
+			desc:     "compact call on full client",
+			copts:    []ClientOption{WithOutputSize(OutputSizeFull)},
+			opts:     []RequestOption{WithCallOutputSize(OutputSizeCompact)},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleTimeSeriesData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			copts := append([]ClientOption{WithAPIKey(testApiKey), WithConnection(conn)}, tt.copts...)
+			client := NewClient(copts...)
+
+			_, _ = client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_requestOptions(t *testing.T) {
+	tests := []struct {
+		desc     string
+		call     func(c *Client) error
+		expected string
+		err      error
+	}{
+		{
+			desc: "full output",
+			call: func(c *Client) error {
+				_, err := c.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", WithFullOutput())
+				return err
+			},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=full&symbol=TEST",
+		},
+		{
+			desc: "later options win",
+			call: func(c *Client) error {
+				_, err := c.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", WithFullOutput(), WithCallOutputSize(OutputSizeCompact))
+				return err
+			},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc: "digital currency",
+			call: func(c *Client) error {
+				_, err := c.DigitalCurrency(context.Background(), "BTC", "USD", WithFullOutput())
+				return err
+			},
+			expected: "query?apikey=test&datatype=csv&function=DIGITAL_CURRENCY_INTRADAY&market=USD&outputsize=full&symbol=BTC",
+		},
+		{
+			desc: "digital currency intraday only",
+			call: func(c *Client) error {
+				_, err := c.DigitalCurrency(context.Background(), "BTC", "USD", WithExtendedHours(false))
+				return err
+			},
+			err: ErrIntradayOnly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleTimeSeriesData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			err := tt.call(client)
+			if tt.err != nil {
+				if err != tt.err {
+					t.Errorf("unexpected error, want %v got %v", tt.err, err)
+				}
+				if conn.endpoint != nil {
+					t.Errorf("request was made to %s", conn.endpoint)
+				}
+				return
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_StockTimeSeries_entitlement(t *testing.T) {
+	tests := []struct {
+		entitlement Entitlement
+		expected    string
+	}{
+		{
+			entitlement: EntitlementNone,
+			expected:    "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			entitlement: EntitlementRealtime,
+			expected:    "query?apikey=test&datatype=csv&entitlement=realtime&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			entitlement: EntitlementDelayed,
+			expected:    "query?apikey=test&datatype=csv&entitlement=delayed&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entitlement.String(), func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleTimeSeriesData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn), WithEntitlement(tt.entitlement))
+
+			_, _ = client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST")
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_StockTimeSeries_queryParam(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []ClientOption
+		call     []RequestOption
+		expected string
+	}{
+		{
+			desc:     "added",
+			opts:     []ClientOption{WithQueryParam("adjusted", "false")},
+			expected: "query?adjusted=false&apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "overrides default",
+			opts:     []ClientOption{WithQueryParam("outputsize", "full")},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=full&symbol=TEST",
+		},
+		{
+			desc:     "overridden by call",
+			opts:     []ClientOption{WithQueryParam("outputsize", "full")},
+			call:     []RequestOption{WithCallOutputSize(OutputSizeCompact)},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "api key kept",
+			opts:     []ClientOption{WithQueryParam("apikey", "other")},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "defaults",
+			opts:     []ClientOption{WithDefaultQueryParams(map[string]string{"gateway": "internal", "outputsize": "full"})},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&gateway=internal&outputsize=full&symbol=TEST",
+		},
+		{
+			desc:     "defaults overridden by call",
+			opts:     []ClientOption{WithDefaultQueryParams(map[string]string{"outputsize": "full"})},
+			call:     []RequestOption{WithCallOutputSize(OutputSizeCompact)},
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleTimeSeriesData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(append([]ClientOption{WithAPIKey(testApiKey), WithConnection(conn)}, tt.opts...)...)
+
+			_, _ = client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", tt.call...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestWithDefaultQueryParams_reserved(t *testing.T) {
+	for _, key := range []string{"apikey", "function", "APIKEY", "Function"} {
+		t.Run(key, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if errors.Cause(err) != ErrReservedQueryParam {
+					t.Errorf("unexpected panic, want %v got %v", ErrReservedQueryParam, err)
+				}
+			}()
+
+			NewClient(WithAPIKey(testApiKey), WithConnection(NewErrorConnection(nil)), WithDefaultQueryParams(map[string]string{key: "other"}))
+			t.Error("client was created with a reserved query parameter")
+		})
+	}
+}
+
+func TestWithDefaultCallTimeout(t *testing.T) {
+	callerDeadline := time.Now().Add(time.Hour)
+	callerCtx, cancel := context.WithDeadline(context.Background(), callerDeadline)
+	defer cancel()
+
+	tests := []struct {
+		desc     string
+		ctx      context.Context
+		deadline func(time.Time) bool
+	}{
+		{
+			desc: "default",
+			ctx:  context.Background(),
+			deadline: func(d time.Time) bool {
+				return d.After(time.Now()) && d.Before(time.Now().Add(time.Minute+time.Second))
+			},
+		},
+		{
+			desc: "caller deadline",
+			ctx:  callerCtx,
+			deadline: func(d time.Time) bool {
+				return d.Equal(callerDeadline)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var requestCtx context.Context
+			conn := connectionFunc(func(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
+				requestCtx = ctx
+				return &http.Response{Body: NewBuffCloser(sampleTimeSeriesData), StatusCode: http.StatusOK}, nil
+			})
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn), WithDefaultCallTimeout(time.Minute))
+
+			values, err := client.StockTimeSeries(tt.ctx, TimeSeriesDaily, "TEST")
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if len(values) == 0 {
+				t.Error("body was not read before the timeout was cancelled")
+			}
+			deadline, ok := requestCtx.Deadline()
+			if !ok || !tt.deadline(deadline) {
+				t.Errorf("unexpected deadline, got %s", deadline)
+			}
+		})
+	}
+}
+
+func TestWithClientName(t *testing.T) {
+	var requests []*http.Request
+	conn := NewConnection(WithTransport(newStubTransport(&requests, sampleTimeSeriesData)))
+	defer conn.(io.Closer).Close()
+
+	named := NewClient(WithAPIKey(testApiKey), WithConnection(conn), WithClientName("tenant"))
+	if _, err := named.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST"); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	unnamed := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+	if _, err := unnamed.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST"); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if name, ok := ClientNameFromContext(requests[0].Context()); !ok || name != "tenant" {
+		t.Errorf("unexpected client name, want tenant got %q", name)
+	}
+	if _, ok := ClientNameFromContext(requests[1].Context()); ok {
+		t.Error("unexpected client name of an unnamed client")
+	}
+	if requests[0].URL.String() != requests[1].URL.String() {
+		t.Errorf("client name changed the request, got %s", requests[0].URL)
+	}
+}
+
 func TestClient_StockTimeSeries_getsResults(t *testing.T) {
 	res := &http.Response{
 		Body:       NewBuffCloser(sampleTimeSeriesData),
@@ -46,3 +389,94 @@ func TestClient_StockTimeSeries_getsResults(t *testing.T) {
 		t.Error("nil results")
 	}
 }
+
+func TestClient_Ping(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=GLOBAL_QUOTE&outputsize=compact&symbol=IBM"
+		quote    = "symbol,open,high,low,price,volume,latestDay,previousClose,change,changePercent\nIBM,195.0900,196.1200,193.2400,195.9500,3840341,2024-03-08,195.9500,0.0000,0.0000%\n"
+	)
+	errNetwork := errors.New("connection refused")
+
+	tests := []struct {
+		desc      string
+		body      string
+		err       error
+		throttled bool
+		apiError  bool
+	}{
+		{desc: "ok", body: quote},
+		{desc: "invalid key", body: sampleErrorMessageData, apiError: true},
+		{desc: "throttled", body: sampleNoteData, apiError: true, throttled: true},
+		{desc: "network", err: errNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var endpoint string
+			conn := connectionFunc(func(ctx context.Context, e *url.URL) (*http.Response, error) {
+				endpoint = e.String()
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &http.Response{Body: NewBuffCloser(tt.body), StatusCode: http.StatusOK}, nil
+			})
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			err := client.Ping(context.Background())
+			if endpoint != expected {
+				t.Errorf("unexpected url, want %s got %s", expected, endpoint)
+			}
+			switch {
+			case tt.apiError:
+				apiErr, ok := err.(*APIError)
+				if !ok {
+					t.Fatalf("unexpected error, want *APIError got %v", err)
+				}
+				if apiErr.Throttled() != tt.throttled {
+					t.Errorf("unexpected throttled, want %t got %t", tt.throttled, apiErr.Throttled())
+				}
+			case err != tt.err:
+				t.Errorf("unexpected error, want %v got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 1000; i++ {
+		client := NewClient(WithAPIKey(testApiKey))
+		if err := client.Close(); err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+	}
+
+	// goroutines exit asynchronously after close
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before+10 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before+10 {
+		t.Errorf("goroutines leaked, %d before and %d after", before, after)
+	}
+}
+
+func TestClient_Close_sharedConnection(t *testing.T) {
+	rl := NewRateLimiter(0, 0)
+	defer rl.Close()
+	conn := NewConnection(WithRateLimiter(rl))
+
+	if err := NewClient(WithConnection(conn)).Close(); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if err := conn.(io.Closer).Close(); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	select {
+	case <-rl.done:
+		t.Error("shared rate limiter was closed")
+	default:
+	}
+}
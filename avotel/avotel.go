@@ -0,0 +1,134 @@
+// avotel traces the requests of an av.Connection with OpenTelemetry, keeping the otel
+// dependency out of the av package.
+//
+// Every request is a client span named after the Alpha Vantage function, parented by the
+// context of the request. Retries are counted when the transport of the connection is
+// wrapped with NewTransport.
+//
+// Usage
+//
+//	base := av.NewConnection(av.WithTransport(avotel.NewTransport(nil)), av.WithRetry(3, time.Second))
+//	conn := avotel.NewConnection(base, avotel.WithTracerProvider(tp))
+//	client := av.NewClient(av.WithAPIKey(key), av.WithConnection(conn))
+package avotel
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	av "github.com/xumr0x/go-alpha-vantage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the name of the tracer of the package
+	tracerName = "github.com/xumr0x/go-alpha-vantage/avotel"
+
+	// AttributeFunction is the Alpha Vantage function of a request, i.e. "TIME_SERIES_DAILY"
+	AttributeFunction = attribute.Key("av.function")
+	// AttributeSymbol is the symbol of a request, if any
+	AttributeSymbol = attribute.Key("av.symbol")
+	// AttributeInterval is the interval of a request, if any
+	AttributeInterval = attribute.Key("av.interval")
+	// AttributeStatusCode is the HTTP status of the response
+	AttributeStatusCode = attribute.Key("http.status_code")
+	// AttributeRetryCount is the number of retries of a request, only set with NewTransport
+	AttributeRetryCount = attribute.Key("av.retry_count")
+)
+
+// config is the configuration of a traced connection
+type config struct {
+	tp trace.TracerProvider
+}
+
+// Option configures the tracing of a connection
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider creating the spans.
+// By default, the global TracerProvider is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tp = tp
+	}
+}
+
+// connection is an av.Connection tracing the requests of another connection
+type connection struct {
+	conn   av.Connection
+	tracer trace.Tracer
+}
+
+// NewConnection creates an av.Connection tracing every request made through conn
+func NewConnection(conn av.Connection, opts ...Option) av.Connection {
+	c := config{tp: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &connection{
+		conn:   conn,
+		tracer: c.tp.Tracer(tracerName),
+	}
+}
+
+// Request makes the request in a span named after its function
+func (c *connection) Request(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
+	query := endpoint.Query()
+	function := query.Get("function")
+
+	attrs := []attribute.KeyValue{AttributeFunction.String(function)}
+	if symbol := query.Get("symbol"); symbol != "" {
+		attrs = append(attrs, AttributeSymbol.String(symbol))
+	}
+	if interval := query.Get("interval"); interval != "" {
+		attrs = append(attrs, AttributeInterval.String(interval))
+	}
+
+	ctx, span := c.tracer.Start(ctx, function, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	attempts := new(int32)
+	response, err := c.conn.Request(context.WithValue(ctx, attemptsKey{}, attempts), endpoint)
+	if n := atomic.LoadInt32(attempts); n > 0 {
+		span.SetAttributes(AttributeRetryCount.Int(int(n - 1)))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(AttributeStatusCode.Int(response.StatusCode))
+	if response.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+	}
+	return response, nil
+}
+
+// attemptsKey is the context key of the attempt count of a traced request
+type attemptsKey struct{}
+
+// transport counts the attempts of traced requests
+type transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps base to count the attempts of the requests traced by NewConnection,
+// recorded as their retry count. A nil base is http.DefaultTransport.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if attempts, ok := req.Context().Value(attemptsKey{}).(*int32); ok {
+		atomic.AddInt32(attempts, 1)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,85 @@
+package avotel
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	av "github.com/xumr0x/go-alpha-vantage"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	sampleTimeSeriesData = `timestamp,open,high,low,close,volume
+2018-01-04 15:55:00,1097.0900,1104.0800,1094.2600,1095.7600,1289293
+2018-01-04 16:00:00,1073.9300,1096.1000,1073.4300,1091.5200,1550593`
+)
+
+// roundTripperFunc is an http.RoundTripper calling a function
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewConnection(t *testing.T) {
+	// the first attempt fails and is retried
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK}
+	stub := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		return &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(strings.NewReader(sampleTimeSeriesData)),
+			Request:    req,
+		}, nil
+	})
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	base := av.NewConnection(av.WithTransport(NewTransport(stub)), av.WithRetry(2, time.Millisecond))
+	conn := NewConnection(base, WithTracerProvider(tp))
+	client := av.NewClient(av.WithAPIKey("test"), av.WithConnection(conn))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.StockTimeSeriesIntraday(ctx, av.TimeIntervalFiveMinute, "IBM")
+	parent.End()
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("unexpected span count, want 2 got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "TIME_SERIES_INTRADAY" {
+		t.Errorf("unexpected span name, want TIME_SERIES_INTRADAY got %s", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("span is not parented by the context of the request")
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		AttributeFunction:   attribute.StringValue("TIME_SERIES_INTRADAY"),
+		AttributeSymbol:     attribute.StringValue("IBM"),
+		AttributeInterval:   attribute.StringValue("5min"),
+		AttributeStatusCode: attribute.IntValue(http.StatusOK),
+		AttributeRetryCount: attribute.IntValue(1),
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	for key, value := range expected {
+		if attrs[key] != value {
+			t.Errorf("unexpected %s, want %s got %s", key, value.Emit(), attrs[key].Emit())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// avtest provides a stub av.Connection, so code using the av package can be tested without the network.
+//
+// Usage
+//
+//	conn := avtest.NewStubConnection()
+//	conn.QueueResponse(http.StatusOK, "timestamp,open,high,low,close,volume\n...")
+//	client := av.NewClient(av.WithAPIKey("test"), av.WithConnection(conn))
+//	values, err := client.StockTimeSeries(ctx, av.TimeSeriesDaily, "GOOGL")
+//	endpoints := conn.Endpoints() // the endpoints requested by the client
+package avtest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	av "github.com/xumr0x/go-alpha-vantage"
+)
+
+// ErrNoResponse is returned by a StubConnection when no response is queued
+var ErrNoResponse = errors.New("avtest: no response queued")
+
+// stubResponse is a queued response or error
+type stubResponse struct {
+	statusCode int
+	body       string
+	err        error
+}
+
+// StubConnection is an av.Connection answering requests with queued responses and errors, in order.
+// It records the endpoint of every request. A StubConnection is safe for concurrent use.
+type StubConnection struct {
+	mu        sync.Mutex
+	responses []stubResponse
+	endpoints []*url.URL
+}
+
+var _ av.Connection = (*StubConnection)(nil)
+
+// NewStubConnection creates a StubConnection without queued responses
+func NewStubConnection() *StubConnection {
+	return &StubConnection{}
+}
+
+// QueueResponse queues a response with the given status code and body
+func (c *StubConnection) QueueResponse(statusCode int, body string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responses = append(c.responses, stubResponse{statusCode: statusCode, body: body})
+}
+
+// QueueError queues an error to be returned instead of a response
+func (c *StubConnection) QueueError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responses = append(c.responses, stubResponse{err: err})
+}
+
+// Request records the endpoint and returns the next queued response or error.
+// ErrNoResponse is returned if the queue is empty.
+func (c *StubConnection) Request(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recorded := *endpoint
+	c.endpoints = append(c.endpoints, &recorded)
+
+	if len(c.responses) == 0 {
+		return nil, ErrNoResponse
+	}
+	next := c.responses[0]
+	c.responses = c.responses[1:]
+
+	if next.err != nil {
+		return nil, next.err
+	}
+	return &http.Response{
+		StatusCode: next.statusCode,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(next.body)),
+	}, nil
+}
+
+// Endpoints returns the endpoints of all requests made so far, in order
+func (c *StubConnection) Endpoints() []*url.URL {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	endpoints := make([]*url.URL, len(c.endpoints))
+	copy(endpoints, c.endpoints)
+	return endpoints
+}
+
+// Pending returns the number of queued responses and errors not yet returned
+func (c *StubConnection) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.responses)
+}
@@ -0,0 +1,53 @@
+package avtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	av "github.com/xumr0x/go-alpha-vantage"
+)
+
+const (
+	sampleTimeSeriesData = `timestamp,open,high,low,close,volume
+2018-01-04,1097.0900,1104.0800,1094.2600,1095.7600,1289293
+2018-01-03,1073.9300,1096.1000,1073.4300,1091.5200,1550593`
+)
+
+func TestStubConnection(t *testing.T) {
+	errStub := errors.New("stub error")
+
+	conn := NewStubConnection()
+	conn.QueueResponse(http.StatusOK, sampleTimeSeriesData)
+	conn.QueueError(errStub)
+	client := av.NewClient(av.WithAPIKey("test"), av.WithConnection(conn))
+
+	values, err := client.StockTimeSeries(context.Background(), av.TimeSeriesDaily, "GOOGL")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected result count, want 2 got %d", len(values))
+	}
+
+	if _, err := client.StockTimeSeries(context.Background(), av.TimeSeriesDaily, "MSFT"); err != errStub {
+		t.Errorf("unexpected error, want %v got %v", errStub, err)
+	}
+	if _, err := client.StockTimeSeries(context.Background(), av.TimeSeriesDaily, "AAPL"); err != ErrNoResponse {
+		t.Errorf("unexpected error, want %v got %v", ErrNoResponse, err)
+	}
+
+	endpoints := conn.Endpoints()
+	if len(endpoints) != 3 {
+		t.Fatalf("unexpected endpoint count, want 3 got %d", len(endpoints))
+	}
+	for i, symbol := range []string{"GOOGL", "MSFT", "AAPL"} {
+		if got := endpoints[i].Query().Get("symbol"); got != symbol {
+			t.Errorf("unexpected symbol at %d, want %s got %s", i, symbol, got)
+		}
+	}
+	if conn.Pending() != 0 {
+		t.Errorf("unexpected pending responses, got %d", conn.Pending())
+	}
+}
@@ -0,0 +1,101 @@
+package av
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tradingDayCache caches successful responses by endpoint until the trading day changes.
+// The trading day is the current date in US/Eastern time.
+type tradingDayCache struct {
+	clock Clock
+	loc   *time.Location
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is a response body cached for a trading day
+type cacheEntry struct {
+	day    string
+	header http.Header
+	body   []byte
+}
+
+func newTradingDayCache(clock Clock) *tradingDayCache {
+	return &tradingDayCache{
+		clock:   clock,
+		loc:     loadTradingDayLocation(),
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// today returns the current trading day
+func (c *tradingDayCache) today() string {
+	return c.clock.Now().In(c.loc).Format(tradingDayFormat)
+}
+
+// get returns the cached response of the endpoint for the current trading day
+func (c *tradingDayCache) get(key string) (*http.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.day != c.today() {
+		return nil, false
+	}
+	return entry.response(), true
+}
+
+// store caches the response of the endpoint, reading its body.
+// Only successful responses with data are cached, Alpha Vantage messages are not.
+// The returned response replaces the given one, as its body has been read.
+func (c *tradingDayCache) store(key string, response *http.Response) (*http.Response, error) {
+	if response.StatusCode != http.StatusOK {
+		return response, nil
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if _, err := checkAPIError(ioutil.NopCloser(bytes.NewReader(body))); err != nil {
+		return response, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	today := c.today()
+	// entries of past trading days are never served again
+	for k, entry := range c.entries {
+		if entry.day != today {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = &cacheEntry{
+		day:    today,
+		header: response.Header,
+		body:   body,
+	}
+	return response, nil
+}
+
+// response creates a response serving the cached body
+func (e *cacheEntry) response() *http.Response {
+	header := make(http.Header, len(e.header))
+	for key, values := range e.header {
+		header[key] = values
+	}
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+	}
+}
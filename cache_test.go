@@ -0,0 +1,73 @@
+package av
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTradingDayCache(t *testing.T) {
+	body := sampleTimeSeriesData
+	var requests []*http.Request
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	conn := NewConnection(WithTransport(transport), WithTradingDayCache()).(*avConnection)
+	defer conn.Close()
+	// 2024-03-07 19:00 in New York, after the market closed
+	clock := newFakeClock()
+	conn.copts.cache.clock = clock
+
+	request := func(path string) string {
+		res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery, RawQuery: path})
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		defer res.Body.Close()
+		data, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		return string(data)
+	}
+
+	if data := request("function=TIME_SERIES_DAILY"); data != sampleTimeSeriesData {
+		t.Errorf("unexpected body, got %q", data)
+	}
+	clock.Advance(4 * time.Hour)
+	if data := request("function=TIME_SERIES_DAILY"); data != sampleTimeSeriesData {
+		t.Errorf("unexpected cached body, got %q", data)
+	}
+	if len(requests) != 1 {
+		t.Errorf("same day request was not cached, got %d requests", len(requests))
+	}
+
+	request("function=TIME_SERIES_WEEKLY")
+	if len(requests) != 2 {
+		t.Errorf("other endpoint was served from the cache, got %d requests", len(requests))
+	}
+
+	// 2024-03-08 00:00 in New York
+	clock.Advance(time.Hour)
+	request("function=TIME_SERIES_DAILY")
+	if len(requests) != 3 {
+		t.Errorf("next day request was served from the cache, got %d requests", len(requests))
+	}
+
+	// alpha vantage messages are not cached
+	body = sampleNoteData
+	request("function=TIME_SERIES_MONTHLY")
+	body = sampleTimeSeriesData
+	if data := request("function=TIME_SERIES_MONTHLY"); data != sampleTimeSeriesData || len(requests) != 5 {
+		t.Errorf("message was cached, got %d requests", len(requests))
+	}
+}
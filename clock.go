@@ -0,0 +1,40 @@
+package av
+
+import (
+	"time"
+)
+
+// Clock tells the time and waits for it to pass.
+// The RateLimiter uses the real clock by default, a fake Clock can be given
+// with WithClock to control time in tests.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a Ticker sending the time every period
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker sends the time on a channel at intervals, like time.Ticker
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the ticker
+	Stop()
+}
+
+// realClock is a Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return &realTicker{t: time.NewTicker(d)} }
+
+// realTicker is a Ticker backed by a time.Ticker
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time { return t.t.C }
+func (t *realTicker) Stop()               { t.t.Stop() }
@@ -0,0 +1,75 @@
+package av
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidCommodity is returned when a commodity is not one of the Commodity* package constants
+var ErrInvalidCommodity = errors.New("invalid commodity")
+
+// ErrInvalidCommodityInterval is returned when a commodity is queried with an unsupported interval
+var ErrInvalidCommodityInterval = errors.New("commodity interval must be monthly, quarterly or annual")
+
+const (
+	// CommodityIntervalMonthly queries monthly commodity prices, the Alpha Vantage default
+	CommodityIntervalMonthly = "monthly"
+	// CommodityIntervalQuarterly queries quarterly commodity prices
+	CommodityIntervalQuarterly = "quarterly"
+	// CommodityIntervalAnnual queries annual commodity prices
+	CommodityIntervalAnnual = "annual"
+)
+
+// Commodity specifies a given commodity to query for.
+// For valid options, see the Commodity* package constants.
+type Commodity uint8
+
+const (
+	CommodityWheat Commodity = iota
+	CommodityCorn
+	CommodityCotton
+	CommoditySugar
+	CommodityCoffee
+)
+
+func (c Commodity) String() string {
+	switch c {
+	case CommodityWheat:
+		return "CommodityWheat"
+	case CommodityCorn:
+		return "CommodityCorn"
+	case CommodityCotton:
+		return "CommodityCotton"
+	case CommoditySugar:
+		return "CommoditySugar"
+	case CommodityCoffee:
+		return "CommodityCoffee"
+	}
+	return "CommodityUnknown"
+}
+
+// keyName returns the name of the Commodity used for Alpha Vantage API
+func (c Commodity) keyName() string {
+	switch c {
+	case CommodityWheat:
+		return "WHEAT"
+	case CommodityCorn:
+		return "CORN"
+	case CommodityCotton:
+		return "COTTON"
+	case CommoditySugar:
+		return "SUGAR"
+	case CommodityCoffee:
+		return "COFFEE"
+	}
+	return "UNKNOWN"
+}
+
+// validCommodityInterval reports whether interval is accepted by the commodity endpoints.
+// An empty interval is accepted and leaves the choice to Alpha Vantage.
+func validCommodityInterval(interval string) bool {
+	switch interval {
+	case "", CommodityIntervalMonthly, CommodityIntervalQuarterly, CommodityIntervalAnnual:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Commodity_buildsUrl(t *testing.T) {
+	tests := []struct {
+		commodity Commodity
+		expected  string
+	}{
+		{
+			commodity: CommodityWheat,
+			expected:  "query?apikey=test&datatype=csv&function=WHEAT&interval=monthly&outputsize=compact",
+		},
+		{
+			commodity: CommodityCoffee,
+			expected:  "query?apikey=test&datatype=csv&function=COFFEE&interval=monthly&outputsize=compact",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.commodity.String(), func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleCommodityData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.Commodity(context.Background(), tt.commodity, CommodityIntervalMonthly)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_Commodity_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleCommodityData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.Commodity(context.Background(), CommodityCorn, CommodityIntervalMonthly)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	// the record without a value is skipped
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if !result[0].Time.Before(result[len(result)-1].Time) {
+		t.Error("results are not sorted from past to present")
+	}
+	if result[0].Value != 236.8901 {
+		t.Errorf("unexpected value, want 236.8901 got %f", result[0].Value)
+	}
+}
+
+func TestClient_Commodity_invalidInterval(t *testing.T) {
+	conn := NewErrorConnection(nil)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	_, err := client.Commodity(context.Background(), CommoditySugar, "daily")
+	if err != ErrInvalidCommodityInterval {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidCommodityInterval, err)
+	}
+	if conn.endpoint != nil {
+		t.Error("request was made for an invalid interval")
+	}
+}
+
+func TestClient_GlobalCommoditiesIndex(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=ALL_COMMODITIES&interval=quarterly&outputsize=compact"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleCommodityData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.GlobalCommoditiesIndex(context.Background(), CommodityIntervalQuarterly)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Errorf("unexpected result count, want 3 got %d", len(result))
+	}
+
+	if _, err := client.GlobalCommoditiesIndex(context.Background(), "weekly"); err != ErrInvalidCommodityInterval {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidCommodityInterval, err)
+	}
+}
+
+func TestClient_Commodity_invalidCommodity(t *testing.T) {
+	conn := NewErrorConnection(nil)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	_, err := client.Commodity(context.Background(), CommodityCoffee+1, CommodityIntervalMonthly)
+	if err != ErrInvalidCommodity {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidCommodity, err)
+	}
+	if conn.endpoint != nil {
+		t.Error("request was made for an invalid commodity")
+	}
+}
+
+func TestClient_Commodity_notCsv(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser("{\n\"Information\": \"rate limited\"\n}"),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	if _, err := client.Commodity(context.Background(), CommodityCorn, CommodityIntervalMonthly); err == nil {
+		t.Error("expected an error for a body which is not a csv")
+	}
+}
@@ -1,9 +1,28 @@
 package av
 
 import (
+	"compress/gzip"
 	"context"
+	"io"
 	"net/http"
 	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrResponseTooLarge is returned when reading a response body larger than the limit set with WithMaxResponseBytes
+var ErrResponseTooLarge = errors.New("response body is too large")
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerUserAgent       = "User-Agent"
+
+	encodingGzip = "gzip"
 )
 
 // Connection is an interface that requests data from a server
@@ -14,14 +33,20 @@ type Connection interface {
 
 type avConnection struct {
 	copts connOptions
+
+	// ownsRateLimiter is set if the rate limiter was created by the connection rather than given
+	ownsRateLimiter bool
 }
 
 func defaultConnOptions() connOptions {
 	return connOptions{
-		client:  &http.Client{},
-		host:    HostDefault,
-		timeout: TimeoutDefault,
-		rl:      NewRateLimiter(0, 0),
+		client:      &http.Client{},
+		scheme:      schemeHttps,
+		host:        HostDefault,
+		timeout:     TimeoutDefault,
+		retryPolicy: DefaultRetryPolicy,
+		maxAttempts: 1,
+		userAgent:   UserAgentDefault,
 	}
 }
 
@@ -35,6 +60,12 @@ func NewConnection(opts ...ConnOption) Connection {
 		opt.apply(&av.copts)
 	}
 
+	// the default rate limiter is only created when none is given, as it runs a goroutine
+	if av.copts.rl == nil {
+		av.copts.rl = NewRateLimiter(0, 0)
+		av.ownsRateLimiter = true
+	}
+
 	return av
 }
 
@@ -50,18 +81,218 @@ func (conn *avConnection) RateLimiter() *RateLimiter {
 	return conn.copts.rl
 }
 
-// Request will make an HTTP GET request for the given endpoint from Alpha Vantage
+// Close releases the resources of the connection.
+// A RateLimiter given with WithRateLimiter is not closed, as it may be shared.
+func (conn *avConnection) Close() error {
+	if conn.ownsRateLimiter {
+		return conn.RateLimiter().Close()
+	}
+	return nil
+}
+
+// Request will make an HTTP GET request for the given endpoint from Alpha Vantage.
+// With WithTradingDayCache, responses cached for the current trading day are served without a request.
+// With WithMetricsHook, the hook is called once the request fails or its response body is closed.
 func (conn *avConnection) Request(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
-	return conn.RateLimiter().Do(func() (*http.Response, error) {
-		endpoint.Scheme = schemeHttps
+	start := time.Now()
+	stats := &requestStats{}
+	response, err := conn.cachedRequest(ctx, endpoint, stats)
+	hook := conn.copts.metricsHook
+	if hook == nil {
+		return response, err
+	}
+
+	name, _ := ClientNameFromContext(ctx)
+	m := newRequestMetrics(name, endpoint, time.Since(start), stats, response, err)
+	if err != nil {
+		hook(m)
+		return nil, err
+	}
+	// the size is only known once the body has been read
+	response.Body = &metricsReadCloser{ReadCloser: response.Body, report: func(size int64) {
+		m.Size = size
+		hook(m)
+	}}
+	return response, nil
+}
+
+// cachedRequest serves the request from the cache, if any, or makes it
+func (conn *avConnection) cachedRequest(ctx context.Context, endpoint *url.URL, stats *requestStats) (*http.Response, error) {
+	cache := conn.copts.cache
+	if cache == nil {
+		return conn.request(ctx, endpoint, stats)
+	}
+
+	key := endpoint.String()
+	if response, ok := cache.get(key); ok {
+		return response, nil
+	}
+	response, err := conn.request(ctx, endpoint, stats)
+	if err != nil {
+		return nil, err
+	}
+	return cache.store(key, response)
+}
+
+// request makes an HTTP GET request through the concurrency and rate limits, with retries
+func (conn *avConnection) request(ctx context.Context, endpoint *url.URL, stats *requestStats) (*http.Response, error) {
+	do := func() (*http.Response, error) {
+		endpoint.Scheme = conn.copts.scheme
 		endpoint.Host = conn.Host()
-		targetUrl := endpoint.String()
+		// the base path is joined on a copy, as the request may be retried
+		target := *endpoint
+		if conn.copts.basePath != "" {
+			target.Path = path.Join("/", conn.copts.basePath, endpoint.Path)
+		}
+		targetUrl := target.String()
 
 		req, err := http.NewRequest(http.MethodGet, targetUrl, nil)
 		if err != nil {
 			return nil, err
 		}
+		for key, values := range conn.copts.header {
+			req.Header[key] = values
+		}
+		if req.Header.Get(headerUserAgent) == "" {
+			req.Header.Set(headerUserAgent, conn.copts.userAgent)
+		}
+		// the encoding is requested explicitly, so the transport leaves decoding to us
+		if req.Header.Get(headerAcceptEncoding) == "" {
+			req.Header.Set(headerAcceptEncoding, encodingGzip)
+		}
+
+		start := time.Now()
+		response, err := conn.Client().Do(req.WithContext(ctx))
+		conn.logRequest(ctx, &target, start, response, err)
+		if err != nil {
+			return nil, err
+		}
+		response, err = decodeResponse(response)
+		if err != nil {
+			return nil, err
+		}
+		if conn.copts.maxBytes > 0 {
+			response.Body = newLimitedReadCloser(response.Body, conn.copts.maxBytes)
+		}
+		return response, nil
+	}
+	if conn.copts.sem == nil {
+		return conn.retry(ctx, do, stats)
+	}
+
+	select {
+	case conn.copts.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-conn.copts.sem }
+
+	response, err := conn.retry(ctx, do, stats)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	response.Body = &releaseReadCloser{ReadCloser: response.Body, release: release}
+	return response, nil
+}
+
+// retry executes the request through the rate limiter, retrying it as set with WithRetry.
+// The response or error of the last attempt is returned.
+func (conn *avConnection) retry(ctx context.Context, do func() (*http.Response, error), stats *requestStats) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		response, err := conn.limit(ctx, do, stats)
+		if attempt >= conn.copts.maxAttempts || err == ErrDailyLimitReached || !conn.copts.retryPolicy(response, err) {
+			return response, err
+		}
+		discardResponse(response)
+
+		select {
+		case <-time.After(retryDelay(conn.copts.baseDelay, attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// limit executes the request through the rate limiter, counting the attempt and its delay
+func (conn *avConnection) limit(ctx context.Context, do func() (*http.Response, error), stats *requestStats) (*http.Response, error) {
+	queued := time.Now()
+	attempt := func() (*http.Response, error) {
+		stats.attempts++
+		stats.delay += time.Since(queued)
+		return do()
+	}
+	if conn.copts.blockOnLimit {
+		return conn.RateLimiter().DoWait(ctx, attempt)
+	}
+	return conn.RateLimiter().Do(attempt)
+}
+
+// releaseReadCloser calls release once when the body is closed
+type releaseReadCloser struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// gzipReadCloser reads a gzip body and closes both the gzip reader and the body
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (r *gzipReadCloser) Close() error {
+	r.Reader.Close()
+	return r.body.Close()
+}
+
+// decodeResponse replaces the body of a gzip encoded response with the decompressed body
+func decodeResponse(response *http.Response) (*http.Response, error) {
+	if !strings.EqualFold(response.Header.Get(headerContentEncoding), encodingGzip) {
+		return response, nil
+	}
+
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	response.Body = &gzipReadCloser{Reader: reader, body: response.Body}
+	response.Header.Del(headerContentEncoding)
+	response.ContentLength = -1
+	response.Uncompressed = true
 
-		return conn.Client().Do(req.WithContext(ctx))
-	})
+	return response, nil
+}
+
+// limitedReadCloser reads up to a limit and then fails with ErrResponseTooLarge
+type limitedReadCloser struct {
+	io.Closer
+	reader *io.LimitedReader
+}
+
+func newLimitedReadCloser(body io.ReadCloser, limit int64) *limitedReadCloser {
+	// one more byte than the limit is allowed, to tell a body at the limit from a larger one
+	return &limitedReadCloser{
+		Closer: body,
+		reader: &io.LimitedReader{R: body, N: limit + 1},
+	}
+}
+
+func (r *limitedReadCloser) Read(p []byte) (int, error) {
+	if r.reader.N <= 0 {
+		return 0, ErrResponseTooLarge
+	}
+	n, err := r.reader.Read(p)
+	if r.reader.N <= 0 {
+		// drop the extra byte
+		return n - 1, ErrResponseTooLarge
+	}
+	return n, err
 }
@@ -0,0 +1,347 @@
+package av
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc is an http.RoundTripper calling a function
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newStubTransport creates an http.RoundTripper responding with body and recording requests
+func newStubTransport(requests *[]*http.Request, body string) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		*requests = append(*requests, req)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestWithTransport(t *testing.T) {
+	var requests []*http.Request
+	conn := NewConnection(
+		WithTimeout(5*time.Second),
+		WithTransport(newStubTransport(&requests, sampleTimeSeriesData)),
+	).(*avConnection)
+
+	if conn.Client().Timeout != 5*time.Second {
+		t.Errorf("timeout was not kept, got %s", conn.Client().Timeout)
+	}
+
+	res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	defer res.Body.Close()
+
+	if len(requests) != 1 {
+		t.Fatalf("request did not go through the transport, got %d requests", len(requests))
+	}
+	if requests[0].URL.Host != HostDefault {
+		t.Errorf("unexpected host, want %s got %s", HostDefault, requests[0].URL.Host)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	tests := []struct {
+		desc      string
+		opts      []ConnOption
+		userAgent string
+	}{
+		{
+			desc:      "default",
+			userAgent: UserAgentDefault,
+		},
+		{
+			desc:      "custom",
+			opts:      []ConnOption{WithUserAgent("my-integration/1.2")},
+			userAgent: "my-integration/1.2",
+		},
+		{
+			desc:      "header",
+			opts:      []ConnOption{WithUserAgent("my-integration/1.2"), WithHeader("User-Agent", "other")},
+			userAgent: "other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var requests []*http.Request
+			conn := NewConnection(append(tt.opts, WithTransport(newStubTransport(&requests, sampleTimeSeriesData)))...)
+			defer conn.(io.Closer).Close()
+
+			res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			res.Body.Close()
+
+			if userAgent := requests[0].Header.Get("User-Agent"); userAgent != tt.userAgent {
+				t.Errorf("unexpected user agent, want %s got %s", tt.userAgent, userAgent)
+			}
+		})
+	}
+}
+
+func TestWithScheme(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []ConnOption
+		expected string
+	}{
+		{desc: "no base path", expected: "/query"},
+		{desc: "base path", opts: []ConnOption{WithBasePath("/alpha")}, expected: "/alpha/query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				w.Write([]byte(sampleTimeSeriesData))
+			}))
+			defer server.Close()
+
+			u, err := url.Parse(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			conn := NewConnection(append([]ConnOption{WithHost(u.Host), WithScheme("http")}, tt.opts...)...)
+			defer conn.(io.Closer).Close()
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			values, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST")
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if len(values) == 0 {
+				t.Error("no values were returned by the test server")
+			}
+			if len(paths) != 1 || paths[0] != tt.expected {
+				t.Errorf("unexpected path, want %s got %v", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		proxied []*http.Request
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r)
+		mu.Unlock()
+		// the tunnel is refused, the request only has to reach the proxy
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	proxyURL.User = url.UserPassword("user", "pass")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	conn := NewConnection(WithHTTPClient(client), WithProxy(proxyURL)).(*avConnection)
+	defer conn.Close()
+
+	if client.Transport != nil {
+		t.Error("transport of the given client was changed")
+	}
+	if conn.Client().Timeout != 5*time.Second {
+		t.Errorf("timeout was not kept, got %s", conn.Client().Timeout)
+	}
+
+	if _, err := conn.Request(context.Background(), &url.URL{Path: pathQuery}); err == nil {
+		t.Fatal("expected an error for the refused tunnel")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(proxied) != 1 {
+		t.Fatalf("request did not go through the proxy, got %d requests", len(proxied))
+	}
+	if r := proxied[0]; r.Method != http.MethodConnect || r.Host != HostDefault+":443" {
+		t.Errorf("unexpected proxy request, got %s %s", r.Method, r.Host)
+	}
+	const expected = "Basic dXNlcjpwYXNz" // user:pass
+	if auth := proxied[0].Header.Get("Proxy-Authorization"); auth != expected {
+		t.Errorf("unexpected proxy authorization, want %s got %s", expected, auth)
+	}
+}
+
+func TestAvConnection_Request_gzip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	writer.Write([]byte(sampleTimeSeriesData))
+	writer.Close()
+	compressed := buf.String()
+
+	tests := []struct {
+		desc     string
+		opts     []ConnOption
+		encoding string
+	}{
+		{
+			desc:     "default",
+			encoding: "gzip",
+		},
+		{
+			desc:     "custom",
+			opts:     []ConnOption{WithHeader("Accept-Encoding", "gzip, deflate")},
+			encoding: "gzip, deflate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var requests []*http.Request
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+					Body:       ioutil.NopCloser(strings.NewReader(compressed)),
+					Request:    req,
+				}, nil
+			})
+			conn := NewConnection(append(tt.opts, WithTransport(transport))...)
+
+			res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			defer res.Body.Close()
+
+			if encoding := requests[0].Header.Get("Accept-Encoding"); encoding != tt.encoding {
+				t.Errorf("unexpected encoding, want %s got %s", tt.encoding, encoding)
+			}
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if string(body) != sampleTimeSeriesData {
+				t.Errorf("body was not decompressed, got %q", body)
+			}
+		})
+	}
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	const (
+		limit    = 2
+		requests = 6
+	)
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+	)
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(sampleTimeSeriesData)),
+			Request:    req,
+		}, nil
+	})
+	conn := NewConnection(WithTransport(transport), WithMaxConcurrency(limit))
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+			if err != nil {
+				t.Errorf("unexpected error, got %v", err)
+				return
+			}
+			// the request is in flight until the body is closed
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen != limit {
+		t.Errorf("unexpected concurrency, want %d got %d", limit, maxSeen)
+	}
+
+	// a blocked request gives up when its context is done
+	first, _ := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+	second, _ := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := conn.Request(ctx, &url.URL{Path: pathQuery}); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error, want %v got %v", context.DeadlineExceeded, err)
+	}
+	first.Body.Close()
+	second.Body.Close()
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	tests := []struct {
+		desc     string
+		limit    int64
+		expected error
+	}{
+		{
+			desc:  "unlimited",
+			limit: 0,
+		},
+		{
+			desc:  "at limit",
+			limit: int64(len(sampleTimeSeriesData)),
+		},
+		{
+			desc:     "over limit",
+			limit:    int64(len(sampleTimeSeriesData)) - 1,
+			expected: ErrResponseTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var requests []*http.Request
+			conn := NewConnection(
+				WithTransport(newStubTransport(&requests, sampleTimeSeriesData)),
+				WithMaxResponseBytes(tt.limit),
+			)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST")
+			if err != tt.expected {
+				t.Errorf("unexpected error, want %v got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package av
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidHTKind is returned when a Hilbert transform indicator can not be queried
+var ErrInvalidHTKind = errors.New("invalid hilbert transform kind")
+
+// Column names of the multi-column Hilbert transform indicators
+const (
+	// ColumnSine is the sine column of HTKindSine
+	ColumnSine = "SINE"
+	// ColumnLeadSine is the lead sine column of HTKindSine
+	ColumnLeadSine = "LEAD SINE"
+	// ColumnPhase is the phase column of HTKindPhasor
+	ColumnPhase = "PHASE"
+	// ColumnQuadrature is the quadrature column of HTKindPhasor
+	ColumnQuadrature = "QUADRATURE"
+)
+
+// HTKind specifies a Hilbert transform indicator.
+// For valid options, see the HTKind* package constants.
+type HTKind uint8
+
+const (
+	// HTKindTrendline is the instantaneous trendline
+	HTKindTrendline HTKind = iota
+	// HTKindSine is the sine wave, with ColumnSine and ColumnLeadSine values
+	HTKindSine
+	// HTKindTrendMode is the trend vs cycle mode
+	HTKindTrendMode
+	// HTKindDCPeriod is the dominant cycle period
+	HTKindDCPeriod
+	// HTKindDCPhase is the dominant cycle phase
+	HTKindDCPhase
+	// HTKindPhasor is the phasor components, with ColumnPhase and ColumnQuadrature values
+	HTKindPhasor
+)
+
+func (k HTKind) String() string {
+	switch k {
+	case HTKindTrendline:
+		return "HTKindTrendline"
+	case HTKindSine:
+		return "HTKindSine"
+	case HTKindTrendMode:
+		return "HTKindTrendMode"
+	case HTKindDCPeriod:
+		return "HTKindDCPeriod"
+	case HTKindDCPhase:
+		return "HTKindDCPhase"
+	case HTKindPhasor:
+		return "HTKindPhasor"
+	}
+	return "HTKindUnknown"
+}
+
+// keyName returns the name of the HTKind function used for Alpha Vantage API
+func (k HTKind) keyName() string {
+	switch k {
+	case HTKindTrendline:
+		return "HT_TRENDLINE"
+	case HTKindSine:
+		return "HT_SINE"
+	case HTKindTrendMode:
+		return "HT_TRENDMODE"
+	case HTKindDCPeriod:
+		return "HT_DCPERIOD"
+	case HTKindDCPhase:
+		return "HT_DCPHASE"
+	case HTKindPhasor:
+		return "HT_PHASOR"
+	}
+	return "UNKNOWN"
+}
+
+// HilbertTransform queries a Hilbert transform indicator of a symbol.
+// HTKindSine and HTKindPhasor return two values, read them with IndicatorValue.Column.
+// ErrInvalidHTKind is returned if kind is not one of the HTKind* package constants.
+// Data is returned from past to present.
+func (c *Client) HilbertTransform(ctx context.Context, kind HTKind, symbol string, interval TimeInterval, seriesType SeriesType) ([]*IndicatorValue, error) {
+	if kind > HTKindPhasor {
+		return nil, ErrInvalidHTKind
+	}
+	return c.technicalIndicator(ctx, kind.keyName(), symbol, interval, map[string]string{
+		querySeriesType: seriesType.keyName(),
+	})
+}
@@ -0,0 +1,109 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_HilbertTransform(t *testing.T) {
+	tests := []struct {
+		kind     HTKind
+		function string
+	}{
+		{kind: HTKindTrendline, function: "HT_TRENDLINE"},
+		{kind: HTKindSine, function: "HT_SINE"},
+		{kind: HTKindTrendMode, function: "HT_TRENDMODE"},
+		{kind: HTKindDCPeriod, function: "HT_DCPERIOD"},
+		{kind: HTKindDCPhase, function: "HT_DCPHASE"},
+		{kind: HTKindPhasor, function: "HT_PHASOR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			expected := "query?apikey=test&datatype=csv&function=" + tt.function + "&interval=daily&outputsize=compact&series_type=close&symbol=TEST"
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleHTTrendlineData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, err := client.HilbertTransform(context.Background(), tt.kind, "TEST", TimeIntervalDaily, SeriesTypeClose)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != expected {
+				t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_HilbertTransform_parse(t *testing.T) {
+	tests := []struct {
+		desc    string
+		kind    HTKind
+		data    string
+		columns []string
+		values  map[string]float64
+	}{
+		{
+			desc:    "single column",
+			kind:    HTKindTrendline,
+			data:    sampleHTTrendlineData,
+			columns: []string{"HT_TRENDLINE"},
+			values:  map[string]float64{"HT_TRENDLINE": 171.2245},
+		},
+		{
+			desc:    "two columns",
+			kind:    HTKindSine,
+			data:    sampleHTSineData,
+			columns: []string{ColumnLeadSine, ColumnSine},
+			values:  map[string]float64{ColumnSine: 0.5878, ColumnLeadSine: 0.9511},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(tt.data),
+				StatusCode: http.StatusOK,
+			}
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(NewResponseConnection(res)))
+
+			result, err := client.HilbertTransform(context.Background(), tt.kind, "TEST", TimeIntervalDaily, SeriesTypeClose)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			last := result[len(result)-1]
+			if columns := last.Columns(); !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("unexpected columns, want %v got %v", tt.columns, columns)
+			}
+			for name, expected := range tt.values {
+				if v, ok := last.Column(name); !ok || v != expected {
+					t.Errorf("unexpected %s value, want %f got %f", name, expected, v)
+				}
+			}
+			if _, ok := last.Column(ColumnQuadrature); ok {
+				t.Errorf("unexpected %s column", ColumnQuadrature)
+			}
+		})
+	}
+}
+
+func TestClient_HilbertTransform_invalidKind(t *testing.T) {
+	conn := NewResponseConnection(&http.Response{})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	if _, err := client.HilbertTransform(context.Background(), HTKindPhasor+1, "TEST", TimeIntervalDaily, SeriesTypeClose); err != ErrInvalidHTKind {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidHTKind, err)
+	}
+	if conn.endpoint != nil {
+		t.Errorf("request was made to %s", conn.endpoint)
+	}
+}
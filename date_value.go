@@ -0,0 +1,109 @@
+package av
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// dateValueMissing is the placeholder Alpha Vantage uses when no value was recorded for a date
+	dateValueMissing = "."
+)
+
+// DateValue is a single value recorded for a given date, such as the price of a commodity
+type DateValue struct {
+	Time  time.Time
+	Value float64
+}
+
+// sortDateValuesByDate allows DateValue
+// slices to be sorted by date in ascending order
+type sortDateValuesByDate []*DateValue
+
+func (b sortDateValuesByDate) Len() int           { return len(b) }
+func (b sortDateValuesByDate) Less(i, j int) bool { return b[i].Time.Before(b[j].Time) }
+func (b sortDateValuesByDate) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// parseDateValueData will parse csv data from a reader.
+// Records without a value are skipped.
+func parseDateValueData(r io.Reader) ([]*DateValue, error) {
+
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true // optimization
+	reader.LazyQuotes = true
+	reader.TrailingComma = true
+	reader.TrimLeadingSpace = true
+
+	// strip header
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	values := make([]*DateValue, 0, 64)
+
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		value, err := parseDateValueRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		values = append(values, value)
+	}
+
+	// sort values by date
+	sort.Sort(sortDateValuesByDate(values))
+
+	return values, nil
+
+}
+
+// parseDateValueRecord will parse an individual csv record.
+// A nil value is returned if the record has no value.
+func parseDateValueRecord(s []string) (*DateValue, error) {
+	// these are the expected columns in the csv record
+	const (
+		timestamp = iota
+		val
+	)
+
+	// the body is not a date value csv, i.e. a message of the API
+	if len(s) <= val {
+		return nil, errors.Errorf("error parsing record %q, want %d columns got %d", s, val+1, len(s))
+	}
+
+	if s[val] == dateValueMissing {
+		return nil, nil
+	}
+
+	value := &DateValue{}
+
+	d, err := parseDate(s[timestamp], timeSeriesDateFormats...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing timestamp %s", s[timestamp])
+	}
+	value.Time = d
+
+	f, err := parseFloat(s[val])
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing value %s", s[val])
+	}
+	value.Value = f
+
+	return value, nil
+}
@@ -0,0 +1,70 @@
+package av
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidDMKind is returned when a directional movement indicator can not be queried
+var ErrInvalidDMKind = errors.New("invalid directional movement kind")
+
+// DMKind specifies a directional movement indicator.
+// For valid options, see the DMKind* package constants.
+type DMKind uint8
+
+const (
+	// DMKindDX is the directional movement index
+	DMKindDX DMKind = iota
+	// DMKindPlusDI is the plus directional indicator
+	DMKindPlusDI
+	// DMKindMinusDI is the minus directional indicator
+	DMKindMinusDI
+	// DMKindPlusDM is the plus directional movement
+	DMKindPlusDM
+	// DMKindMinusDM is the minus directional movement
+	DMKindMinusDM
+)
+
+func (k DMKind) String() string {
+	switch k {
+	case DMKindDX:
+		return "DMKindDX"
+	case DMKindPlusDI:
+		return "DMKindPlusDI"
+	case DMKindMinusDI:
+		return "DMKindMinusDI"
+	case DMKindPlusDM:
+		return "DMKindPlusDM"
+	case DMKindMinusDM:
+		return "DMKindMinusDM"
+	}
+	return "DMKindUnknown"
+}
+
+// keyName returns the name of the DMKind function used for Alpha Vantage API
+func (k DMKind) keyName() string {
+	switch k {
+	case DMKindDX:
+		return "DX"
+	case DMKindPlusDI:
+		return "PLUS_DI"
+	case DMKindMinusDI:
+		return "MINUS_DI"
+	case DMKindPlusDM:
+		return "PLUS_DM"
+	case DMKindMinusDM:
+		return "MINUS_DM"
+	}
+	return "UNKNOWN"
+}
+
+// DirectionalMovement queries a directional movement indicator of a symbol.
+// ErrInvalidDMKind is returned if kind is not one of the DMKind* package constants.
+// Data is returned from past to present.
+func (c *Client) DirectionalMovement(ctx context.Context, kind DMKind, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	if kind > DMKindMinusDM {
+		return nil, ErrInvalidDMKind
+	}
+	return c.periodIndicator(ctx, kind.keyName(), symbol, interval, timePeriod)
+}
@@ -0,0 +1,58 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_DirectionalMovement(t *testing.T) {
+	tests := []struct {
+		kind     DMKind
+		function string
+	}{
+		{kind: DMKindDX, function: "DX"},
+		{kind: DMKindPlusDI, function: "PLUS_DI"},
+		{kind: DMKindMinusDI, function: "MINUS_DI"},
+		{kind: DMKindPlusDM, function: "PLUS_DM"},
+		{kind: DMKindMinusDM, function: "MINUS_DM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			expected := "query?apikey=test&datatype=csv&function=" + tt.function + "&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleDirectionalMovementData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.DirectionalMovement(context.Background(), tt.kind, "TEST", TimeIntervalDaily, 14)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != expected {
+				t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != 27.1934 {
+				t.Errorf("unexpected value, want 27.1934 got %f", v)
+			}
+		})
+	}
+}
+
+func TestClient_DirectionalMovement_invalidKind(t *testing.T) {
+	conn := NewResponseConnection(&http.Response{})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	if _, err := client.DirectionalMovement(context.Background(), DMKindMinusDM+1, "TEST", TimeIntervalDaily, 14); err != ErrInvalidDMKind {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidDMKind, err)
+	}
+	if conn.endpoint != nil {
+		t.Errorf("request was made to %s", conn.endpoint)
+	}
+}
@@ -0,0 +1,119 @@
+package av
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	valueDividendsEndpoint = "DIVIDENDS"
+
+	// dividendDateFormat is the format of the dates of a dividend event
+	dividendDateFormat = "2006-01-02"
+)
+
+// DividendEvent is a past or announced dividend of a symbol.
+// Dates which are not known are zero.
+type DividendEvent struct {
+	ExDividendDate  time.Time
+	DeclarationDate time.Time
+	RecordDate      time.Time
+	PaymentDate     time.Time
+	Amount          float64
+}
+
+// sortDividendEventsByDate allows DividendEvent
+// slices to be sorted by ex-dividend date in ascending order
+type sortDividendEventsByDate []*DividendEvent
+
+func (b sortDividendEventsByDate) Len() int { return len(b) }
+func (b sortDividendEventsByDate) Less(i, j int) bool {
+	return b[i].ExDividendDate.Before(b[j].ExDividendDate)
+}
+func (b sortDividendEventsByDate) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+
+// dividendData is the json body of a dividends response
+type dividendData struct {
+	Symbol string `json:"symbol"`
+	Data   []struct {
+		ExDividendDate  string `json:"ex_dividend_date"`
+		DeclarationDate string `json:"declaration_date"`
+		RecordDate      string `json:"record_date"`
+		PaymentDate     string `json:"payment_date"`
+		Amount          string `json:"amount"`
+	} `json:"data"`
+}
+
+// parseDividendData will parse json data from a reader
+func parseDividendData(r io.Reader) ([]*DividendEvent, error) {
+	var data dividendData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding dividends")
+	}
+
+	events := make([]*DividendEvent, 0, len(data.Data))
+	for _, d := range data.Data {
+		event := &DividendEvent{}
+
+		dates := []struct {
+			name   string
+			value  string
+			target *time.Time
+		}{
+			{"ex dividend date", d.ExDividendDate, &event.ExDividendDate},
+			{"declaration date", d.DeclarationDate, &event.DeclarationDate},
+			{"record date", d.RecordDate, &event.RecordDate},
+			{"payment date", d.PaymentDate, &event.PaymentDate},
+		}
+		for _, date := range dates {
+			t, err := parseDividendDate(date.value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error parsing %s %s", date.name, date.value)
+			}
+			*date.target = t
+		}
+
+		f, err := parseFloat(d.Amount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing amount %s", d.Amount)
+		}
+		event.Amount = f
+
+		events = append(events, event)
+	}
+
+	// sort events by date
+	sort.Sort(sortDividendEventsByDate(events))
+
+	return events, nil
+}
+
+// parseDividendDate parses a date of a dividend event, a missing date is zero
+func parseDividendDate(v string) (time.Time, error) {
+	switch v {
+	case "", "None", "null":
+		return time.Time{}, nil
+	}
+	return parseDate(v, dividendDateFormat)
+}
+
+// Dividends queries the historical and announced dividends of a symbol.
+// Data is returned from past to present, by ex-dividend date.
+func (c *Client) Dividends(ctx context.Context, symbol string) ([]*DividendEvent, error) {
+	endpoint := c.buildRequestPath(map[string]string{
+		queryEndpoint: valueDividendsEndpoint,
+		querySymbol:   symbol,
+		queryDataType: valueDataTypeJson,
+	})
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseDividendData(body)
+}
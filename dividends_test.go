@@ -0,0 +1,48 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_Dividends(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=json&function=DIVIDENDS&outputsize=compact&symbol=IBM"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleDividendData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	events, err := client.Dividends(context.Background(), "IBM")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(events) != 2 {
+		t.Fatalf("unexpected event count, want 2 got %d", len(events))
+	}
+
+	past := &DividendEvent{
+		ExDividendDate:  time.Date(2024, 2, 8, 0, 0, 0, 0, time.UTC),
+		DeclarationDate: time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC),
+		RecordDate:      time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC),
+		PaymentDate:     time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+		Amount:          1.66,
+	}
+	if !reflect.DeepEqual(events[0], past) {
+		t.Errorf("unexpected past dividend, want %+v got %+v", past, events[0])
+	}
+	// dates of an announced dividend which are not known yet are zero
+	announced := events[1]
+	if announced.Amount != 1.67 || !announced.RecordDate.IsZero() || !announced.PaymentDate.IsZero() {
+		t.Errorf("unexpected announced dividend, got %+v", announced)
+	}
+}
@@ -0,0 +1,41 @@
+package av
+
+const (
+	queryEntitlement = "entitlement"
+)
+
+// Entitlement specifies the data entitlement of a premium API key.
+// For valid options, see the Entitlement* package constants.
+type Entitlement uint8
+
+const (
+	// EntitlementNone omits the entitlement, the free tier behavior
+	EntitlementNone Entitlement = iota
+	// EntitlementRealtime queries realtime data
+	EntitlementRealtime
+	// EntitlementDelayed queries 15-minute delayed data
+	EntitlementDelayed
+)
+
+func (e Entitlement) String() string {
+	switch e {
+	case EntitlementNone:
+		return "EntitlementNone"
+	case EntitlementRealtime:
+		return "EntitlementRealtime"
+	case EntitlementDelayed:
+		return "EntitlementDelayed"
+	}
+	return "EntitlementUnknown"
+}
+
+// keyName returns the name of the Entitlement used for Alpha Vantage API
+func (e Entitlement) keyName() string {
+	switch e {
+	case EntitlementRealtime:
+		return "realtime"
+	case EntitlementDelayed:
+		return "delayed"
+	}
+	return ""
+}
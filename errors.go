@@ -0,0 +1,94 @@
+package av
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	// apiErrorPeekSize is the number of bytes inspected to detect a message response
+	apiErrorPeekSize = 64
+)
+
+// APIError is a message returned by Alpha Vantage instead of the requested data,
+// i.e. for an invalid API call or when the API rate limit has been exceeded.
+type APIError struct {
+	// Key is the key of the message in the response, one of "Error Message", "Note" or "Information"
+	Key string
+	// Message is the text of the message
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("alpha vantage %s: %s", e.Key, e.Message)
+}
+
+// Throttled reports whether the message is a rate limit notice
+// rather than the rejection of an invalid API call.
+func (e *APIError) Throttled() bool {
+	return e.Key != apiMessageError
+}
+
+const (
+	apiMessageError       = "Error Message"
+	apiMessageNote        = "Note"
+	apiMessageInformation = "Information"
+)
+
+// apiMessage is the json body Alpha Vantage returns instead of data
+type apiMessage struct {
+	ErrorMessage string `json:"Error Message"`
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+}
+
+// err returns the message as an *APIError, or nil if there is no message
+func (m *apiMessage) err() error {
+	switch {
+	case m.ErrorMessage != "":
+		return &APIError{Key: apiMessageError, Message: m.ErrorMessage}
+	case m.Note != "":
+		return &APIError{Key: apiMessageNote, Message: m.Note}
+	case m.Information != "":
+		return &APIError{Key: apiMessageInformation, Message: m.Information}
+	}
+	return nil
+}
+
+// readCloser reads from a Reader and closes a Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// checkAPIError inspects a response body for a message returned instead of data.
+// An *APIError is returned if a message is found, otherwise a body with
+// the same contents is returned. The given body is not closed on error.
+func checkAPIError(body io.ReadCloser) (io.ReadCloser, error) {
+	reader := bufio.NewReaderSize(body, apiErrorPeekSize)
+
+	// messages are json objects, data is csv or a json object
+	peek, _ := reader.Peek(apiErrorPeekSize)
+	peek = bytes.TrimLeft(peek, " \t\r\n")
+	if len(peek) == 0 || peek[0] != '{' {
+		return &readCloser{Reader: reader, Closer: body}, nil
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var message apiMessage
+	if err := json.Unmarshal(data, &message); err == nil {
+		if err := message.err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &readCloser{Reader: bytes.NewReader(data), Closer: body}, nil
+}
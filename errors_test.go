@@ -0,0 +1,81 @@
+package av
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_StockTimeSeries_apiError(t *testing.T) {
+	tests := []struct {
+		desc      string
+		data      string
+		key       string
+		throttled bool
+	}{
+		{
+			desc:      "information",
+			data:      sampleInformationData,
+			key:       "Information",
+			throttled: true,
+		},
+		{
+			desc:      "note",
+			data:      sampleNoteData,
+			key:       "Note",
+			throttled: true,
+		},
+		{
+			desc: "error message",
+			data: sampleErrorMessageData,
+			key:  "Error Message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(tt.data),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST")
+			if result != nil {
+				t.Errorf("unexpected results, got %v", result)
+			}
+			apiErr, ok := err.(*APIError)
+			if !ok {
+				t.Fatalf("unexpected error, want *APIError got %v", err)
+			}
+			if apiErr.Key != tt.key {
+				t.Errorf("unexpected key, want %s got %s", tt.key, apiErr.Key)
+			}
+			if apiErr.Message == "" || !strings.Contains(apiErr.Error(), apiErr.Message) {
+				t.Errorf("message text is missing, got %v", apiErr)
+			}
+			if apiErr.Throttled() != tt.throttled {
+				t.Errorf("unexpected throttled, want %v got %v", tt.throttled, apiErr.Throttled())
+			}
+		})
+	}
+}
+
+func TestCheckAPIError_data(t *testing.T) {
+	for _, data := range []string{sampleTimeSeriesData, `{"Realtime Currency Exchange Rate": {}}`, ""} {
+		body, err := checkAPIError(NewBuffCloser(data))
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		if string(b) != data {
+			t.Errorf("body was modified, want %q got %q", data, b)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package av
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	queryFromCurrency = "from_currency"
+	queryToCurrency   = "to_currency"
+
+	valueExchangeRateEndpoint = "CURRENCY_EXCHANGE_RATE"
+
+	// exchangeRateDateFormat is the format of the last refreshed time of an exchange rate
+	exchangeRateDateFormat = "2006-01-02 15:04:05"
+)
+
+// ExchangeRate is the realtime exchange rate of a digital or physical currency to another currency
+type ExchangeRate struct {
+	FromCode      string
+	FromName      string
+	ToCode        string
+	ToName        string
+	Rate          float64
+	Bid           float64
+	Ask           float64
+	LastRefreshed time.Time
+}
+
+// exchangeRateData is the json body of an exchange rate response
+type exchangeRateData struct {
+	Rate struct {
+		FromCode      string `json:"1. From_Currency Code"`
+		FromName      string `json:"2. From_Currency Name"`
+		ToCode        string `json:"3. To_Currency Code"`
+		ToName        string `json:"4. To_Currency Name"`
+		Rate          string `json:"5. Exchange Rate"`
+		LastRefreshed string `json:"6. Last Refreshed"`
+		TimeZone      string `json:"7. Time Zone"`
+		Bid           string `json:"8. Bid Price"`
+		Ask           string `json:"9. Ask Price"`
+	} `json:"Realtime Currency Exchange Rate"`
+}
+
+// parseExchangeRateData will parse json data from a reader
+func parseExchangeRateData(r io.Reader) (*ExchangeRate, error) {
+	var data exchangeRateData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding exchange rate")
+	}
+	d := data.Rate
+
+	rate := &ExchangeRate{
+		FromCode: d.FromCode,
+		FromName: d.FromName,
+		ToCode:   d.ToCode,
+		ToName:   d.ToName,
+	}
+
+	f, err := parseFloat(d.Rate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing exchange rate %s", d.Rate)
+	}
+	rate.Rate = f
+
+	// bid and ask prices are not always known
+	if d.Bid != "" && d.Bid != "-" {
+		if rate.Bid, err = parseFloat(d.Bid); err != nil {
+			return nil, errors.Wrapf(err, "error parsing bid price %s", d.Bid)
+		}
+	}
+	if d.Ask != "" && d.Ask != "-" {
+		if rate.Ask, err = parseFloat(d.Ask); err != nil {
+			return nil, errors.Wrapf(err, "error parsing ask price %s", d.Ask)
+		}
+	}
+
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(exchangeRateDateFormat, d.LastRefreshed, loc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing last refreshed %s", d.LastRefreshed)
+	}
+	rate.LastRefreshed = t
+
+	return rate, nil
+}
+
+// CurrencyExchangeRate queries the realtime exchange rate of a digital or physical currency
+// to another currency, i.e. "BTC" to "USD".
+func (c *Client) CurrencyExchangeRate(ctx context.Context, from string, to string) (*ExchangeRate, error) {
+	endpoint := c.buildRequestPath(map[string]string{
+		queryEndpoint:     valueExchangeRateEndpoint,
+		queryFromCurrency: from,
+		queryToCurrency:   to,
+		queryDataType:     valueDataTypeJson,
+	})
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseExchangeRateData(body)
+}
+
+// CurrencyExchangeRates queries the realtime exchange rates of a currency to each of the given
+// currencies, i.e. "USD" to "EUR" and "JPY". The rates are queried concurrently through the
+// rate limits of the connection and returned by the code of the currency they are to.
+// An error is returned for every rate which could not be queried, in the order of to.
+// Rates not queried yet when ctx is done fail with the error of the context.
+func (c *Client) CurrencyExchangeRates(ctx context.Context, from string, to []string) (map[string]*ExchangeRate, []error) {
+	rates := make([]*ExchangeRate, len(to))
+	errs := make([]error, len(to))
+
+	wg := &sync.WaitGroup{}
+	for i, code := range to {
+		if err := ctx.Err(); err != nil {
+			errs[i] = errors.Wrapf(err, "error querying %s to %s", from, code)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, code string) {
+			defer wg.Done()
+			rate, err := c.CurrencyExchangeRate(ctx, from, code)
+			if err != nil {
+				errs[i] = errors.Wrapf(err, "error querying %s to %s", from, code)
+				return
+			}
+			rates[i] = rate
+		}(i, code)
+	}
+	wg.Wait()
+
+	result := make(map[string]*ExchangeRate, len(to))
+	var failed []error
+	for i, code := range to {
+		if errs[i] != nil {
+			failed = append(failed, errs[i])
+			continue
+		}
+		result[code] = rates[i]
+	}
+	return result, failed
+}
+
+// CryptoSpotPrice queries the realtime price of a digital currency in a physical currency,
+// i.e. "BTC" in "USD", and the time it was last refreshed.
+func (c *Client) CryptoSpotPrice(ctx context.Context, crypto string, fiat string) (float64, time.Time, error) {
+	rate, err := c.CurrencyExchangeRate(ctx, crypto, fiat)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return rate.Rate, rate.LastRefreshed, nil
+}
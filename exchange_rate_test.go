@@ -0,0 +1,106 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_CurrencyExchangeRate(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=json&from_currency=BTC&function=CURRENCY_EXCHANGE_RATE&outputsize=compact&to_currency=USD"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleExchangeRateData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	rate, err := client.CurrencyExchangeRate(context.Background(), "BTC", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if rate.FromCode != "BTC" || rate.ToName != "United States Dollar" {
+		t.Errorf("unexpected currencies, got %+v", rate)
+	}
+	if rate.Rate != 67012.34 || rate.Bid != 67012.33 || rate.Ask != 67012.34 {
+		t.Errorf("unexpected prices, got %+v", rate)
+	}
+}
+
+func TestClient_CryptoSpotPrice(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleExchangeRateData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	price, refreshed, err := client.CryptoSpotPrice(context.Background(), "BTC", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if price != 67012.34 {
+		t.Errorf("unexpected price, want 67012.34 got %f", price)
+	}
+	if expected := time.Date(2024, 3, 8, 21, 5, 1, 0, time.UTC); !refreshed.Equal(expected) {
+		t.Errorf("unexpected last refreshed, want %s got %s", expected, refreshed)
+	}
+}
+
+func TestClient_CurrencyExchangeRates(t *testing.T) {
+	errUnknown := errors.New("unknown currency")
+	conn := connectionFunc(func(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
+		if endpoint.Query().Get(queryToCurrency) == "XXX" {
+			return nil, errUnknown
+		}
+		return &http.Response{
+			Body:       NewBuffCloser(sampleExchangeRateData),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	rates, errs := client.CurrencyExchangeRates(context.Background(), "BTC", []string{"USD", "XXX", "EUR"})
+	if len(errs) != 1 || errors.Cause(errs[0]) != errUnknown {
+		t.Fatalf("unexpected errors, got %v", errs)
+	}
+	if len(rates) != 2 || rates["USD"] == nil || rates["EUR"] == nil {
+		t.Errorf("unexpected rates, got %v", rates)
+	}
+	if _, ok := rates["XXX"]; ok {
+		t.Error("rate returned for a failed pair")
+	}
+}
+
+func TestClient_CurrencyExchangeRates_canceled(t *testing.T) {
+	var requests int32
+	conn := connectionFunc(func(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return nil, ctx.Err()
+	})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rates, errs := client.CurrencyExchangeRates(ctx, "USD", []string{"EUR", "JPY"})
+	if len(rates) != 0 {
+		t.Errorf("unexpected rates, got %v", rates)
+	}
+	if len(errs) != 2 || errors.Cause(errs[0]) != context.Canceled {
+		t.Errorf("unexpected errors, got %v", errs)
+	}
+	if requests != 0 {
+		t.Errorf("unexpected request count, want 0 got %d", requests)
+	}
+}
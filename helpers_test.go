@@ -3,14 +3,30 @@ package av
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
 	testApiKey = "test"
 )
 
+// generateTimeSeriesData creates csv data of a daily time series with the given number of rows,
+// from present to past like Alpha Vantage
+func generateTimeSeriesData(rows int) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString("timestamp,open,high,low,close,volume\n")
+	day := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < rows; i++ {
+		price := 100 + float64(i%50)
+		fmt.Fprintf(buf, "%s,%.4f,%.4f,%.4f,%.4f,%d\n", day.Format("2006-01-02"), price, price+1, price-1, price+0.5, 1000000+i)
+		day = day.AddDate(0, 0, -1)
+	}
+	return buf.String()
+}
+
 type responseConnection struct {
 	endpoint *url.URL
 	response *http.Response
@@ -39,6 +55,13 @@ func (c *errorConnection) Request(ctx context.Context, endpoint *url.URL) (*http
 	return nil, c.err
 }
 
+// connectionFunc implements Connection with a function, so it can respond to concurrent requests
+type connectionFunc func(ctx context.Context, endpoint *url.URL) (*http.Response, error)
+
+func (f connectionFunc) Request(ctx context.Context, endpoint *url.URL) (*http.Response, error) {
+	return f(ctx, endpoint)
+}
+
 type ResetBuffer struct {
 	contents string
 	buf      *bytes.Buffer
@@ -168,3 +191,414 @@ const (
 2017-08-15,941.0300,943.0700,936.6400,938.0800,1006064
 2017-08-14,939.0700,941.0400,934.4900,938.9300,1140212`
 )
+
+const (
+	sampleDividendData = `{
+    "symbol": "IBM",
+    "data": [
+        {
+            "ex_dividend_date": "2024-05-09",
+            "declaration_date": "2024-04-30",
+            "record_date": "None",
+            "payment_date": "None",
+            "amount": "1.67"
+        },
+        {
+            "ex_dividend_date": "2024-02-08",
+            "declaration_date": "2024-01-30",
+            "record_date": "2024-02-09",
+            "payment_date": "2024-03-09",
+            "amount": "1.66"
+        }
+    ]
+}`
+
+	sampleSplitData = `{
+    "symbol": "IBM",
+    "data": [
+        {
+            "effective_date": "2021-11-04",
+            "split_factor": "1.0460"
+        },
+        {
+            "effective_date": "1999-05-27",
+            "split_factor": "2.0000"
+        }
+    ]
+}`
+
+	sampleListingData = `symbol,name,exchange,assetType,ipoDate,delistingDate,status
+A,Agilent Technologies Inc,NYSE,Stock,1999-11-18,null,Active
+AA,Alcoa Corp,NYSE,Stock,2016-10-18,null,Active
+AAA,Alternative Access First Priority CLO Bond ETF,NYSE ARCA,ETF,2020-09-09,null,Active
+`
+
+	sampleCommodityData = `timestamp,value
+2024-03-01,215.1234
+2024-02-01,.
+2024-01-01,229.4567
+2023-12-01,236.8901`
+)
+
+const (
+	sampleSTOCHData = `time,SlowK,SlowD
+2024-03-08 16:00,38.1852,52.4075
+2024-03-08 15:45,47.6471,61.3354
+2024-03-08 15:30,71.3901,68.7120
+2024-03-08 15:15,64.9694,66.4127`
+)
+
+const (
+	sampleADXData = `time,ADX
+2024-03-08,23.5108
+2024-03-07,22.4761
+2024-03-06,21.8820`
+
+	sampleATRData = `time,ATR
+2024-03-08 16:00,0.4871
+2024-03-08 15:00,0.5012
+2024-03-08 14:00,0.4930`
+)
+
+const (
+	sampleSMAData = `time,SMA
+2024-03-08,407.8810
+2024-03-01,404.3240
+2024-02-23,402.1130`
+
+	sampleMACDData = `time,MACD,MACD_Hist,MACD_Signal
+2024-03-08,5.2311,-0.4120,5.6431
+2024-03-07,5.8842,0.1209,5.7633
+2024-03-06,5.7108,0.0210,5.6898`
+)
+
+const (
+	sampleOBVData = `time,OBV
+2024-03-08 16:00,31946730.0000
+2024-03-08 15:55,29823113.0000
+2024-03-08 15:50,30112520.0000`
+
+	sampleChaikinData = `time,Chaikin A/D
+2024-03-08,1234567.8901
+2024-03-07,-234567.1234
+2024-03-06,345678.5678`
+
+	sampleVWAPData = `time,VWAP
+2024-03-08 16:00,170.8121
+2024-03-08 15:55,170.7934
+2024-03-08 15:50,170.7702`
+
+	sampleOBVLargeData = `time,OBV
+2024-03-08,-12345678901.0000
+2024-03-07,4294967296.0000
+2024-03-06,98765432109.0000`
+)
+
+const (
+	sampleEMAData = `time,EMA
+2024-03-08,406.2214
+2024-03-07,405.0173
+2024-03-06,403.8891`
+)
+
+const (
+	sampleMAMAData = `time,MAMA,FAMA
+2024-03-08,408.3871,401.7734
+2024-03-07,409.1102,401.4433
+2024-03-06,407.9920,401.0129`
+)
+
+const (
+	sampleADXRData = `time,ADXR
+2024-03-08,20.1432
+2024-03-07,19.8716
+2024-03-06,19.5509`
+
+	samplePriceOscillatorData = `time,PPO
+2024-03-08,1.8211
+2024-03-07,1.5873
+2024-03-06,-0.2466`
+
+	sampleAROONData = `time,Aroon Down,Aroon Up
+2024-03-08,7.1429,100.0000
+2024-03-07,14.2857,92.8571
+2024-03-06,21.4286,85.7143`
+
+	sampleAROONOSCData = `time,AROONOSC
+2024-03-08,92.8571
+2024-03-07,78.5714
+2024-03-06,64.2857`
+
+	sampleBOPData = `time,BOP
+2024-03-08,0.3261
+2024-03-07,-0.0923
+2024-03-06,-0.7457`
+
+	sampleCMOData = `time,CMO
+2024-03-08,-42.1873
+2024-03-07,-8.0354
+2024-03-06,27.5530
+2024-03-05,63.0091`
+
+	sampleCCIData = `time,CCI
+2024-03-08,-184.3312
+2024-03-07,-42.0871
+2024-03-06,35.6120
+2024-03-05,121.9034
+2024-03-04,236.4788`
+
+	sampleMFIData = `time,MFI
+2024-03-08,61.2290
+2024-03-07,55.8734
+2024-03-06,48.0127`
+
+	sampleMOMData = `time,MOM
+2024-03-08,4.1200
+2024-03-07,-1.3500
+2024-03-06,2.0800`
+
+	sampleROCData = `time,ROC
+2024-03-08,2.4734
+2024-03-07,-0.7921
+2024-03-06,1.2345`
+
+	sampleROCRData = `time,ROCR
+2024-03-08,1.0247
+2024-03-07,0.9921
+2024-03-06,1.0123`
+
+	sampleULTOSCData = `time,ULTOSC
+2024-03-08,58.3917
+2024-03-07,54.1022
+2024-03-06,49.8765`
+
+	sampleWILLRData = `time,WILLR
+2024-03-08,-100.0000
+2024-03-07,-100.0000
+2024-03-06,-63.4521
+2024-03-05,-12.0934
+2024-03-04,0.0000`
+
+	sampleTRIXData = `time,TRIX
+2024-03-08,0.1342
+2024-03-07,0.1287
+2024-03-06,0.1215`
+
+	sampleDirectionalMovementData = `time,DX
+2024-03-08,27.1934
+2024-03-07,24.8801
+2024-03-06,19.0457`
+
+	sampleHTTrendlineData = `time,HT_TRENDLINE
+2024-03-08,171.2245
+2024-03-07,170.8813
+2024-03-06,170.5507`
+
+	sampleHTSineData = `time,LEAD SINE,SINE
+2024-03-08,0.9511,0.5878
+2024-03-07,0.8090,0.3090
+2024-03-06,0.5878,0.0000`
+
+	sampleRSIData = `time,RSI
+2024-03-08,59.3470
+2024-03-07,71.0042
+2024-03-06,66.8217
+2024-03-05,28.4410
+2024-03-04,35.9951`
+)
+
+const (
+	sampleSTOCHFData = `time,FastK,FastD
+2024-03-08,12.4587,35.7812
+2024-03-07,41.0263,52.3341
+2024-03-06,53.8586,60.1204`
+)
+
+const (
+	sampleBBANDSData = `time,Real Lower Band,Real Upper Band,Real Middle Band
+2024-03-08,395.1934,418.0526,406.6230
+2024-03-07,394.8710,417.2010,406.0360
+2024-03-06,393.9911,416.4309,405.2110`
+)
+
+const (
+	sampleInformationData = `{
+    "Information": "We have detected your API key as test and our standard API rate limit is 25 requests per day. Please subscribe to any of the premium plans at https://www.alphavantage.co/premium/ to instantly remove all daily rate limits."
+}`
+
+	sampleNoteData = `{
+    "Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute and 500 calls per day."
+}`
+
+	sampleErrorMessageData = `{
+    "Error Message": "Invalid API call. Please retry or visit the documentation (https://www.alphavantage.co/documentation/) for TIME_SERIES_DAILY."
+}`
+)
+
+const (
+	sampleTimeSeriesAdjustedData = `timestamp,open,high,low,close,adjusted_close,volume,dividend_amount,split_coefficient
+2024-03-08,169.0000,173.7000,168.9400,170.7300,170.7300,76114634,0.0000,1.0
+2024-03-07,169.1500,170.7300,168.4900,169.0000,169.0000,71765061,0.0000,1.0
+2024-02-09,188.6500,189.9900,188.0000,188.8500,188.6200,45155216,0.2400,1.0`
+
+	sampleTimeSeriesAdjustedJSONData = `{
+    "Meta Data": {
+        "1. Information": "Daily Time Series with Splits and Dividend Events",
+        "2. Symbol": "AAPL",
+        "3. Last Refreshed": "2024-03-08",
+        "4. Output Size": "Compact",
+        "5. Time Zone": "US/Eastern"
+    },
+    "Time Series (Daily)": {
+        "2024-03-08": {
+            "1. open": "169.0000",
+            "2. high": "173.7000",
+            "3. low": "168.9400",
+            "4. close": "170.7300",
+            "5. adjusted close": "170.7300",
+            "6. volume": "76114634",
+            "7. dividend amount": "0.0000",
+            "8. split coefficient": "1.0"
+        },
+        "2024-03-07": {
+            "1. open": "169.1500",
+            "2. high": "170.7300",
+            "3. low": "168.4900",
+            "4. close": "169.0000",
+            "5. adjusted close": "169.0000",
+            "6. volume": "71765061",
+            "7. dividend amount": "0.0000",
+            "8. split coefficient": "1.0"
+        },
+        "2024-02-09": {
+            "1. open": "188.6500",
+            "2. high": "189.9900",
+            "3. low": "188.0000",
+            "4. close": "188.8500",
+            "5. adjusted close": "188.6200",
+            "6. volume": "45155216",
+            "7. dividend amount": "0.2400",
+            "8. split coefficient": "1.0"
+        }
+    }
+}`
+
+	sampleTimeSeriesWeeklyAdjustedData = `timestamp,open,high,low,close,adjusted close,volume,dividend amount
+2024-03-08,175.0000,176.4500,168.4900,170.7300,170.7300,297437532,0.0000
+2024-03-01,181.2700,183.9200,177.3800,179.6600,179.6600,283018437,0.0000
+2024-02-09,187.1500,191.0500,185.8400,188.8500,188.6200,256101829,0.2400`
+)
+
+const (
+	sampleNATRData = `time,NATR
+2024-03-08,1.3122
+2024-03-07,1.2876
+2024-03-06,1.3301`
+
+	sampleTRANGEData = `time,TRANGE
+2024-03-08,4.7600
+2024-03-07,2.2400
+2024-03-06,3.0100`
+
+	sampleMIDPOINTData = `time,MIDPOINT
+2024-03-08,171.2350
+2024-03-07,171.9000
+2024-03-06,172.4150`
+
+	sampleMIDPRICEData = `time,MIDPRICE
+2024-03-08,171.9850
+2024-03-07,172.6400
+2024-03-06,173.1200`
+
+	sampleSARData = `time,SAR
+2024-03-08,168.4900
+2024-03-07,167.9021
+2024-03-06,175.1200`
+)
+
+const (
+	sampleTimeSeriesInvalidData = `timestamp,open,high,low,close,volume
+2018-01-04,1097.0900,1104.0800,1094.2600,1095.7600,1289293
+2018-01-03,1073.9300,N/A,1073.4300,1091.5200,1550593
+2018-01-02,1053.0200,1075.9800,1053.0200,1073.2100,1555809`
+
+	sampleTimeSeriesExtendedHoursData = `timestamp,open,high,low,close,volume
+2024-03-08 19:55:00,170.5100,170.6000,170.4500,170.5500,21534
+2024-03-08 15:55:00,170.2000,170.8500,170.1500,170.7300,2153412
+2024-03-08 09:30:00,169.0000,169.4200,168.9400,169.2100,1832190
+2024-03-08 04:00:00,169.3000,169.5500,169.1000,169.2500,8231`
+
+	sampleTimeSeriesHeaderOnlyData = `timestamp,open,high,low,close,volume
+`
+
+	sampleTimeSeriesInconsistentData = `timestamp,open,high,low,close,volume
+2018-01-04,1097.0900,1104.0800,1094.2600,1095.7600,1289293
+2018-01-03,1073.9300,1096.1000,1073.4300,1091.5200,1550593
+2018-01-02,1053.0200,1075.9800,1053.0200,1079.1100,1555809`
+)
+
+const (
+	sampleAnalyticsData = `{
+    "meta_data": {
+        "symbols": "AAPL,MSFT,IBM",
+        "min_dt": "2023-07-03",
+        "max_dt": "2023-08-31",
+        "ohlc": "Close",
+        "interval": "DAILY"
+    },
+    "payload": {
+        "RETURNS_CALCULATIONS": {
+            "MEAN": {
+                "AAPL": 0.0012,
+                "MSFT": -0.0004,
+                "IBM": 0.0003
+            },
+            "CORRELATION": {
+                "index": ["AAPL", "MSFT", "IBM"],
+                "correlation": [[1.0], [0.6123, 1.0], [0.2045, 0.3318, 1.0]]
+            }
+        }
+    }
+}`
+
+	sampleAnalyticsWindowData = `{
+    "meta_data": {
+        "symbols": "AAPL,IBM",
+        "window_size": 10,
+        "min_dt": "2023-07-03",
+        "max_dt": "2023-07-19",
+        "ohlc": "Close",
+        "interval": "DAILY"
+    },
+    "payload": {
+        "RETURNS_CALCULATIONS": {
+            "MEAN": {
+                "RUNNING_MEAN": {
+                    "AAPL": {"2023-07-19": 0.0021, "2023-07-18": 0.0015},
+                    "IBM": {"2023-07-19": -0.0008, "2023-07-18": 0.0002}
+                }
+            },
+            "STDDEV": {
+                "RUNNING_STDDEV": {
+                    "AAPL": {"2023-07-19": 0.0113, "2023-07-18": 0.0109},
+                    "IBM": {"2023-07-19": 0.0087, "2023-07-18": 0.0091}
+                }
+            }
+        }
+    }
+}`
+
+	sampleExchangeRateData = `{
+    "Realtime Currency Exchange Rate": {
+        "1. From_Currency Code": "BTC",
+        "2. From_Currency Name": "Bitcoin",
+        "3. To_Currency Code": "USD",
+        "4. To_Currency Name": "United States Dollar",
+        "5. Exchange Rate": "67012.34000000",
+        "6. Last Refreshed": "2024-03-08 21:05:01",
+        "7. Time Zone": "UTC",
+        "8. Bid Price": "67012.33000000",
+        "9. Ask Price": "67012.34000000"
+    }
+}`
+)
@@ -0,0 +1,169 @@
+package av
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	queryDate  = "date"
+	queryState = "state"
+
+	valueListingStatusEndpoint = "LISTING_STATUS"
+
+	// ListingStateActive queries symbols which are listed
+	ListingStateActive = "active"
+	// ListingStateDelisted queries symbols which were delisted
+	ListingStateDelisted = "delisted"
+
+	// listingDateFormat is the format of the date param and the dates of a listing
+	listingDateFormat = "2006-01-02"
+	// listingDateMissing is the placeholder Alpha Vantage uses when a listing has no date
+	listingDateMissing = "null"
+)
+
+// ListedSymbol is a symbol listed, or delisted, on a US exchange
+type ListedSymbol struct {
+	Symbol    string
+	Name      string
+	Exchange  string
+	AssetType string
+	IPODate   time.Time
+	// DelistingDate is zero for symbols which are still listed
+	DelistingDate time.Time
+	Status        string
+}
+
+// parseListingData will parse csv data from a reader
+func parseListingData(r io.Reader) ([]*ListedSymbol, error) {
+
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true // optimization
+	reader.LazyQuotes = true
+	reader.TrailingComma = true
+	reader.TrimLeadingSpace = true
+
+	// strip header
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	symbols := make([]*ListedSymbol, 0, 64)
+
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		symbol, err := parseListingRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, nil
+}
+
+// parseListingRecord will parse an individual csv record
+func parseListingRecord(s []string) (*ListedSymbol, error) {
+	// these are the expected columns in the csv record
+	const (
+		symbol = iota
+		name
+		exchange
+		assetType
+		ipoDate
+		delistingDate
+		status
+	)
+
+	listed := &ListedSymbol{
+		Symbol:    s[symbol],
+		Name:      s[name],
+		Exchange:  s[exchange],
+		AssetType: s[assetType],
+		Status:    s[status],
+	}
+
+	var err error
+	if listed.IPODate, err = parseListingDate(s[ipoDate]); err != nil {
+		return nil, errors.Wrapf(err, "error parsing ipo date %s", s[ipoDate])
+	}
+	if listed.DelistingDate, err = parseListingDate(s[delistingDate]); err != nil {
+		return nil, errors.Wrapf(err, "error parsing delisting date %s", s[delistingDate])
+	}
+
+	return listed, nil
+}
+
+// parseListingDate parses a date of a listing, a missing date is zero
+func parseListingDate(v string) (time.Time, error) {
+	if v == "" || v == listingDateMissing {
+		return time.Time{}, nil
+	}
+	return parseDate(v, listingDateFormat)
+}
+
+// ListingStatus queries the symbols listed on US exchanges on a given date.
+// A zero date queries the latest trading day.
+// The state is one of the ListingState* package constants,
+// an empty state queries the active symbols.
+func (c *Client) ListingStatus(ctx context.Context, date time.Time, state string) ([]*ListedSymbol, error) {
+	params := map[string]string{
+		queryEndpoint: valueListingStatusEndpoint,
+	}
+	if state != "" {
+		params[queryState] = state
+	}
+	if !date.IsZero() {
+		params[queryDate] = date.Format(listingDateFormat)
+	}
+	endpoint := c.buildRequestPath(params)
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseListingData(body)
+}
+
+// DiffListings compares two snapshots of listed symbols by symbol, ignoring case.
+// Added are the symbols of new which are not in old,
+// removed are the symbols of old which are not in new.
+func DiffListings(old, new []*ListedSymbol) (added, removed []*ListedSymbol) {
+	oldSymbols := listingSymbols(old)
+	newSymbols := listingSymbols(new)
+
+	for _, s := range new {
+		if _, ok := oldSymbols[strings.ToUpper(s.Symbol)]; !ok {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if _, ok := newSymbols[strings.ToUpper(s.Symbol)]; !ok {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// listingSymbols returns the set of upper case symbols of a listing
+func listingSymbols(listing []*ListedSymbol) map[string]struct{} {
+	symbols := make(map[string]struct{}, len(listing))
+	for _, s := range listing {
+		symbols[strings.ToUpper(s.Symbol)] = struct{}{}
+	}
+	return symbols
+}
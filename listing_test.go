@@ -0,0 +1,77 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_ListingStatus(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&date=2020-10-01&function=LISTING_STATUS&outputsize=compact&state=active"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleListingData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	date := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	symbols, err := client.ListingStatus(context.Background(), date, ListingStateActive)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(symbols) != 3 {
+		t.Fatalf("unexpected symbol count, want 3 got %d", len(symbols))
+	}
+	first := symbols[0]
+	if first.Symbol != "A" || first.Exchange != "NYSE" || first.AssetType != "Stock" {
+		t.Errorf("unexpected symbol %+v", first)
+	}
+	if !first.IPODate.Equal(time.Date(1999, 11, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected ipo date, got %v", first.IPODate)
+	}
+	if !first.DelistingDate.IsZero() {
+		t.Errorf("unexpected delisting date, got %v", first.DelistingDate)
+	}
+}
+
+func TestClient_ListingStatus_defaults(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=LISTING_STATUS&outputsize=compact"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleListingData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	if _, err := client.ListingStatus(context.Background(), time.Time{}, ""); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+}
+
+func TestDiffListings(t *testing.T) {
+	a := &ListedSymbol{Symbol: "A"}
+	aa := &ListedSymbol{Symbol: "AA"}
+	aaa := &ListedSymbol{Symbol: "AAA"}
+	lowerAA := &ListedSymbol{Symbol: "aa"}
+
+	added, removed := DiffListings([]*ListedSymbol{a, aa}, []*ListedSymbol{lowerAA, aaa})
+	if !reflect.DeepEqual(added, []*ListedSymbol{aaa}) {
+		t.Errorf("unexpected added symbols, got %v", added)
+	}
+	if !reflect.DeepEqual(removed, []*ListedSymbol{a}) {
+		t.Errorf("unexpected removed symbols, got %v", removed)
+	}
+}
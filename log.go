@@ -0,0 +1,72 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// redactedAPIKey replaces the API key in logged URLs and errors
+	redactedAPIKey = "REDACTED"
+)
+
+// Logger logs the requests of a connection at debug level, see WithLogger.
+// Key value pairs follow the message, i.e. "status", 200. A *slog.Logger is a Logger.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+}
+
+// logRequest logs a request made to the endpoint, with the API key redacted.
+// The status is only logged for a response, and the error only for a failed request.
+func (conn *avConnection) logRequest(ctx context.Context, endpoint *url.URL, start time.Time, response *http.Response, err error) {
+	logger := conn.copts.logger
+	if logger == nil {
+		return
+	}
+
+	keyvals := []interface{}{
+		"method", http.MethodGet,
+		"url", redactURL(endpoint).String(),
+		"duration", time.Since(start),
+	}
+	if name, ok := ClientNameFromContext(ctx); ok {
+		keyvals = append(keyvals, "client", name)
+	}
+	if response != nil {
+		keyvals = append(keyvals, "status", response.StatusCode)
+	}
+	if err != nil {
+		keyvals = append(keyvals, "error", redactError(err).Error())
+	}
+	logger.Debug("alpha vantage request", keyvals...)
+}
+
+// redactURL returns a copy of u with the value of the API key query parameter redacted
+func redactURL(u *url.URL) *url.URL {
+	redacted := *u
+	query := redacted.Query()
+	if query.Get(queryApiKey) != "" {
+		query.Set(queryApiKey, redactedAPIKey)
+		redacted.RawQuery = query.Encode()
+	}
+	return &redacted
+}
+
+// redactError redacts the API key in the URL of errors returned by the http.Client
+func redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	redacted := *urlErr
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		// the key cannot be located, so none of the URL is kept
+		redacted.URL = redactedAPIKey
+	} else {
+		redacted.URL = redactURL(u).String()
+	}
+	return &redacted
+}
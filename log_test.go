@@ -0,0 +1,112 @@
+package av
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// testLogger records every logged line
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Debug(msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(append([]interface{}{msg}, keyvals...)...))
+}
+
+func TestWithLogger(t *testing.T) {
+	const apiKey = "s3cr3t-key"
+
+	tests := []struct {
+		desc      string
+		transport http.RoundTripper
+		contains  []string
+	}{
+		{
+			desc:      "response",
+			transport: newStubTransport(&[]*http.Request{}, sampleTimeSeriesData),
+			contains:  []string{"GET", "apikey=" + redactedAPIKey, "status", "200", "client", "tenant"},
+		},
+		{
+			desc: "error",
+			transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			}),
+			contains: []string{"GET", "apikey=" + redactedAPIKey, "error", "connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			logger := &testLogger{}
+			conn := NewConnection(WithTransport(tt.transport), WithLogger(logger))
+			defer conn.(io.Closer).Close()
+			client := NewClient(WithAPIKey(apiKey), WithConnection(conn), WithClientName("tenant"))
+
+			_, _ = client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST")
+
+			if len(logger.lines) != 1 {
+				t.Fatalf("unexpected log lines, want 1 got %d", len(logger.lines))
+			}
+			line := logger.lines[0]
+			if strings.Contains(line, apiKey) {
+				t.Errorf("api key was logged: %s", line)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(line, s) {
+					t.Errorf("%q was not logged: %s", s, line)
+				}
+			}
+		})
+	}
+}
+
+func TestWithLogger_shortAPIKey(t *testing.T) {
+	// the key also occurs in the host and the symbol, which are kept as is
+	const apiKey = "test"
+
+	for _, fails := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fails=%t", fails), func(t *testing.T) {
+			transport := newStubTransport(&[]*http.Request{}, sampleTimeSeriesData)
+			if fails {
+				transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					return nil, errors.New("connection refused")
+				})
+			}
+			logger := &testLogger{}
+			conn := NewConnection(WithTransport(transport), WithLogger(logger), WithHost("test.example.com"))
+			defer conn.(io.Closer).Close()
+			client := NewClient(WithAPIKey(apiKey), WithConnection(conn))
+
+			_, _ = client.StockTimeSeries(context.Background(), TimeSeriesDaily, "test")
+
+			if len(logger.lines) != 1 {
+				t.Fatalf("unexpected log lines, want 1 got %d", len(logger.lines))
+			}
+			line := logger.lines[0]
+			if strings.Contains(line, "apikey="+apiKey) {
+				t.Errorf("api key was logged: %s", line)
+			}
+			// the url is logged again in the error of a failed request
+			want := 1
+			if fails {
+				want = 2
+			}
+			for _, s := range []string{"test.example.com/query", "apikey=" + redactedAPIKey, "symbol=test"} {
+				if got := strings.Count(line, s); got != want {
+					t.Errorf("unexpected count of %q, want %d got %d: %s", s, want, got, line)
+				}
+			}
+		})
+	}
+}
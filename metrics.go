@@ -0,0 +1,78 @@
+package av
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitedDelay is the delay from which a request counts as delayed by the rate limiter
+	rateLimitedDelay = time.Millisecond
+)
+
+// RequestMetrics describes a request of a connection, see WithMetricsHook
+type RequestMetrics struct {
+	// ClientName is the name of the client making the request, see WithClientName
+	ClientName string
+	// Function is the Alpha Vantage function queried, i.e. "TIME_SERIES_DAILY"
+	Function string
+	// Status is the HTTP status of the response, zero if the request failed
+	Status int
+	// Duration is the time taken by the request, including waiting for the rate limiter and retries
+	Duration time.Duration
+	// Size is the number of bytes of the response body read before it was closed, after decompression
+	Size int64
+	// Attempts is the number of requests made, more than one if retried and zero if served from cache
+	Attempts int
+	// RateLimited is set if the rate limiter delayed the request, RateLimitDelay being the total delay
+	RateLimited    bool
+	RateLimitDelay time.Duration
+	// Err is the error of a failed request
+	Err error
+}
+
+// requestStats are collected while making a request
+type requestStats struct {
+	attempts int
+	delay    time.Duration
+}
+
+// newRequestMetrics creates the metrics of a request to the endpoint
+func newRequestMetrics(clientName string, endpoint *url.URL, duration time.Duration, stats *requestStats, response *http.Response, err error) RequestMetrics {
+	m := RequestMetrics{
+		ClientName:     clientName,
+		Function:       endpoint.Query().Get(queryEndpoint),
+		Duration:       duration,
+		Attempts:       stats.attempts,
+		RateLimited:    stats.delay >= rateLimitedDelay,
+		RateLimitDelay: stats.delay,
+		Err:            err,
+	}
+	if response != nil {
+		m.Status = response.StatusCode
+	}
+	return m
+}
+
+// metricsReadCloser counts the bytes read from a body and reports them once when it is closed
+type metricsReadCloser struct {
+	io.ReadCloser
+	size   int64
+	once   sync.Once
+	report func(size int64)
+}
+
+func (r *metricsReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *metricsReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(func() { r.report(r.size) })
+	return err
+}
@@ -0,0 +1,159 @@
+package av
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestWithMetricsHook(t *testing.T) {
+	transportErr := errors.New("connection refused")
+
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	writer.Write([]byte(sampleTimeSeriesData))
+	writer.Close()
+	compressed := buf.String()
+
+	tests := []struct {
+		desc   string
+		status int
+		gzip   bool
+		err    error
+	}{
+		{desc: "success", status: http.StatusOK},
+		{desc: "gzip", status: http.StatusOK, gzip: true},
+		{desc: "http error", status: http.StatusServiceUnavailable},
+		{desc: "transport error", err: transportErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				if tt.gzip {
+					return &http.Response{
+						StatusCode:    tt.status,
+						Header:        http.Header{"Content-Encoding": []string{"gzip"}},
+						ContentLength: int64(len(compressed)),
+						Body:          ioutil.NopCloser(strings.NewReader(compressed)),
+						Request:       req,
+					}, nil
+				}
+				return &http.Response{
+					StatusCode:    tt.status,
+					ContentLength: int64(len(sampleTimeSeriesData)),
+					Body:          ioutil.NopCloser(strings.NewReader(sampleTimeSeriesData)),
+					Request:       req,
+				}, nil
+			})
+			var metrics []RequestMetrics
+			conn := NewConnection(WithTransport(transport), WithMetricsHook(func(m RequestMetrics) {
+				metrics = append(metrics, m)
+			}))
+			defer conn.(io.Closer).Close()
+
+			endpoint := &url.URL{Path: pathQuery, RawQuery: "function=TIME_SERIES_DAILY&symbol=TEST"}
+			res, err := conn.Request(context.Background(), endpoint)
+			if err == nil {
+				if _, err := ioutil.ReadAll(res.Body); err != nil {
+					t.Fatalf("unexpected error, got %v", err)
+				}
+				if len(metrics) != 0 {
+					t.Fatal("hook was called before the body was closed")
+				}
+				res.Body.Close()
+				res.Body.Close()
+			}
+
+			if len(metrics) != 1 {
+				t.Fatalf("unexpected hook calls, want 1 got %d", len(metrics))
+			}
+			m := metrics[0]
+			if m.Function != "TIME_SERIES_DAILY" {
+				t.Errorf("unexpected function, want TIME_SERIES_DAILY got %s", m.Function)
+			}
+			if m.Status != tt.status {
+				t.Errorf("unexpected status, want %d got %d", tt.status, m.Status)
+			}
+			if m.Attempts != 1 || m.Duration <= 0 || m.RateLimited {
+				t.Errorf("unexpected metrics, got %+v", m)
+			}
+			if tt.err != nil {
+				if m.Err == nil || m.Size != 0 {
+					t.Errorf("unexpected metrics of a failed request, got %+v", m)
+				}
+			} else if m.Err != nil || m.Size != int64(len(sampleTimeSeriesData)) {
+				t.Errorf("unexpected metrics of a response, got %+v", m)
+			}
+		})
+	}
+}
+
+func TestWithMetricsHook_rateLimited(t *testing.T) {
+	var requests []*http.Request
+	var metrics []RequestMetrics
+	rl := NewRateLimiter(0, 20)
+	defer rl.Close()
+	conn := NewConnection(
+		WithTransport(newStubTransport(&requests, sampleTimeSeriesData)),
+		WithRateLimiter(rl),
+		WithMetricsHook(func(m RequestMetrics) {
+			metrics = append(metrics, m)
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		res.Body.Close()
+	}
+
+	if len(metrics) != 2 {
+		t.Fatalf("unexpected hook calls, want 2 got %d", len(metrics))
+	}
+	if metrics[0].RateLimited {
+		t.Errorf("first request was rate limited by %s", metrics[0].RateLimitDelay)
+	}
+	// 20 requests per second are spaced by 50ms
+	if !metrics[1].RateLimited || metrics[1].RateLimitDelay < 25*time.Millisecond {
+		t.Errorf("second request was not rate limited, got %+v", metrics[1])
+	}
+}
+
+func TestWithMetricsHook_clientName(t *testing.T) {
+	var requests []*http.Request
+	var metrics []RequestMetrics
+	conn := NewConnection(
+		WithTransport(newStubTransport(&requests, sampleTimeSeriesData)),
+		WithMetricsHook(func(m RequestMetrics) {
+			metrics = append(metrics, m)
+		}),
+	)
+	defer conn.(io.Closer).Close()
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn), WithClientName("tenant"))
+
+	if _, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST"); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if len(metrics) != 1 {
+		t.Fatalf("unexpected hook calls, want 1 got %d", len(metrics))
+	}
+	if metrics[0].ClientName != "tenant" {
+		t.Errorf("unexpected client name, want tenant got %q", metrics[0].ClientName)
+	}
+}
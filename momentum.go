@@ -0,0 +1,343 @@
+package av
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	queryFastPeriod   = "fastperiod"
+	querySlowPeriod   = "slowperiod"
+	querySignalPeriod = "signalperiod"
+	queryFastMAType   = "fastmatype"
+	querySlowMAType   = "slowmatype"
+	querySignalMAType = "signalmatype"
+	queryFastDPeriod  = "fastdperiod"
+	queryFastDMAType  = "fastdmatype"
+	queryTimePeriod1  = "timeperiod1"
+	queryTimePeriod2  = "timeperiod2"
+	queryTimePeriod3  = "timeperiod3"
+
+	valueADXEndpoint      = "ADX"
+	valueADXREndpoint     = "ADXR"
+	valueAPOEndpoint      = "APO"
+	valuePPOEndpoint      = "PPO"
+	valueAROONEndpoint    = "AROON"
+	valueAROONOSCEndpoint = "AROONOSC"
+	valueBOPEndpoint      = "BOP"
+	valueCCIEndpoint      = "CCI"
+	valueCMOEndpoint      = "CMO"
+	valueMACDEndpoint     = "MACD"
+	valueMACDEXTEndpoint  = "MACDEXT"
+	valueMFIEndpoint      = "MFI"
+	valueMOMEndpoint      = "MOM"
+	valueROCEndpoint      = "ROC"
+	valueROCREndpoint     = "ROCR"
+	valueRSIEndpoint      = "RSI"
+	valueTRIXEndpoint     = "TRIX"
+	valueULTOSCEndpoint   = "ULTOSC"
+	valueWILLREndpoint    = "WILLR"
+	valueSTOCHFEndpoint   = "STOCHF"
+	valueSTOCHRSIEndpoint = "STOCHRSI"
+
+	columnMACD       = "MACD"
+	columnMACDHist   = "MACD_Hist"
+	columnMACDSignal = "MACD_Signal"
+	columnFastK      = "FastK"
+	columnFastD      = "FastD"
+	columnAroonUp    = "Aroon Up"
+	columnAroonDown  = "Aroon Down"
+)
+
+// MACDValue is a piece of data for a given time about the moving average convergence / divergence
+type MACDValue struct {
+	Time   time.Time
+	MACD   float64
+	Hist   float64
+	Signal float64
+}
+
+// MACD queries the moving average convergence / divergence of a symbol.
+// The fast, slow and signal periods default to 12, 26 and 9 and can be
+// changed with WithFastPeriod, WithSlowPeriod and WithSignalPeriod.
+// Data is returned from past to present.
+func (c *Client) MACD(ctx context.Context, symbol string, interval TimeInterval, seriesType SeriesType, opts ...IndicatorOption) ([]*MACDValue, error) {
+	params := indicatorParams(map[string]string{
+		queryFastPeriod:   "12",
+		querySlowPeriod:   "26",
+		querySignalPeriod: "9",
+	}, opts)
+	params[querySeriesType] = seriesType.keyName()
+
+	return c.macd(ctx, valueMACDEndpoint, symbol, interval, params)
+}
+
+// MACDEXT queries the moving average convergence / divergence of a symbol
+// with controllable moving average types.
+// The fast, slow and signal periods default to 12, 26 and 9 and can be
+// changed with WithFastPeriod, WithSlowPeriod and WithSignalPeriod.
+// The moving average types default to MATypeSMA and can be changed
+// with WithFastMAType, WithSlowMAType and WithSignalMAType.
+// Data is returned from past to present.
+func (c *Client) MACDEXT(ctx context.Context, symbol string, interval TimeInterval, seriesType SeriesType, opts ...IndicatorOption) ([]*MACDValue, error) {
+	params := indicatorParams(map[string]string{
+		queryFastPeriod:   "12",
+		querySlowPeriod:   "26",
+		querySignalPeriod: "9",
+		queryFastMAType:   "0",
+		querySlowMAType:   "0",
+		querySignalMAType: "0",
+	}, opts)
+	params[querySeriesType] = seriesType.keyName()
+
+	return c.macd(ctx, valueMACDEXTEndpoint, symbol, interval, params)
+}
+
+// macd queries a MACD indicator and maps its columns to MACDValue
+func (c *Client) macd(ctx context.Context, function string, symbol string, interval TimeInterval, params map[string]string) ([]*MACDValue, error) {
+	values, err := c.technicalIndicator(ctx, function, symbol, interval, params)
+	if err != nil {
+		return nil, err
+	}
+
+	macd := make([]*MACDValue, 0, len(values))
+	for _, value := range values {
+		v, err := value.lookup(columnMACD, columnMACDHist, columnMACDSignal)
+		if err != nil {
+			return nil, err
+		}
+		macd = append(macd, &MACDValue{
+			Time:   value.Time,
+			MACD:   v[0],
+			Hist:   v[1],
+			Signal: v[2],
+		})
+	}
+	return macd, nil
+}
+
+// ADX queries the average directional movement index of a symbol.
+// Data is returned from past to present.
+func (c *Client) ADX(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueADXEndpoint, symbol, interval, timePeriod)
+}
+
+// ADXR queries the average directional movement index rating of a symbol.
+// Data is returned from past to present.
+func (c *Client) ADXR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueADXREndpoint, symbol, interval, timePeriod)
+}
+
+// APO queries the absolute price oscillator of a symbol.
+// The fast and slow periods default to 12 and 26 and can be changed
+// with WithFastPeriod and WithSlowPeriod.
+// The moving average type defaults to MATypeSMA and can be changed with WithMAType.
+// Data is returned from past to present.
+func (c *Client) APO(ctx context.Context, symbol string, interval TimeInterval, seriesType SeriesType, opts ...IndicatorOption) ([]*IndicatorValue, error) {
+	return c.priceOscillator(ctx, valueAPOEndpoint, symbol, interval, seriesType, opts)
+}
+
+// PPO queries the percentage price oscillator of a symbol.
+// The fast and slow periods default to 12 and 26 and can be changed
+// with WithFastPeriod and WithSlowPeriod.
+// The moving average type defaults to MATypeSMA and can be changed with WithMAType.
+// Data is returned from past to present.
+func (c *Client) PPO(ctx context.Context, symbol string, interval TimeInterval, seriesType SeriesType, opts ...IndicatorOption) ([]*IndicatorValue, error) {
+	return c.priceOscillator(ctx, valuePPOEndpoint, symbol, interval, seriesType, opts)
+}
+
+// priceOscillator queries a price oscillator, i.e. APO
+func (c *Client) priceOscillator(ctx context.Context, function string, symbol string, interval TimeInterval, seriesType SeriesType, opts []IndicatorOption) ([]*IndicatorValue, error) {
+	params := indicatorParams(map[string]string{
+		queryFastPeriod: "12",
+		querySlowPeriod: "26",
+		queryMAType:     "0",
+	}, opts)
+	params[querySeriesType] = seriesType.keyName()
+
+	return c.technicalIndicator(ctx, function, symbol, interval, params)
+}
+
+// AROONValue is a piece of data for a given time about the aroon indicator
+type AROONValue struct {
+	Time      time.Time
+	AroonUp   float64
+	AroonDown float64
+}
+
+// AROON queries the aroon up and aroon down lines of a symbol.
+// Data is returned from past to present.
+func (c *Client) AROON(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*AROONValue, error) {
+	values, err := c.periodIndicator(ctx, valueAROONEndpoint, symbol, interval, timePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	aroon := make([]*AROONValue, 0, len(values))
+	for _, value := range values {
+		v, err := value.lookup(columnAroonUp, columnAroonDown)
+		if err != nil {
+			return nil, err
+		}
+		aroon = append(aroon, &AROONValue{
+			Time:      value.Time,
+			AroonUp:   v[0],
+			AroonDown: v[1],
+		})
+	}
+	return aroon, nil
+}
+
+// AROONOSC queries the aroon oscillator of a symbol.
+// Data is returned from past to present.
+func (c *Client) AROONOSC(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueAROONOSCEndpoint, symbol, interval, timePeriod)
+}
+
+// BOP queries the balance of power of a symbol.
+// Data is returned from past to present.
+func (c *Client) BOP(ctx context.Context, symbol string, interval TimeInterval) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueBOPEndpoint, symbol, interval, nil)
+}
+
+// CCI queries the commodity channel index of a symbol.
+// Data is returned from past to present.
+func (c *Client) CCI(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueCCIEndpoint, symbol, interval, timePeriod)
+}
+
+// CMO queries the Chande momentum oscillator of a symbol.
+// Data is returned from past to present.
+func (c *Client) CMO(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueCMOEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// MFI queries the money flow index of a symbol.
+// Data is returned from past to present.
+func (c *Client) MFI(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueMFIEndpoint, symbol, interval, timePeriod)
+}
+
+// MOM queries the momentum of a symbol.
+// Data is returned from past to present.
+func (c *Client) MOM(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueMOMEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// ROC queries the rate of change of a symbol.
+// Data is returned from past to present.
+func (c *Client) ROC(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueROCEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// ROCR queries the rate of change ratio of a symbol.
+// Data is returned from past to present.
+func (c *Client) ROCR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueROCREndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// TRIX queries the rate of change of a triple smooth exponential moving average of a symbol.
+// Data is returned from past to present.
+func (c *Client) TRIX(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueTRIXEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// RSI queries the relative strength index of a symbol.
+// Data is returned from past to present.
+func (c *Client) RSI(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueRSIEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// ULTOSC queries the ultimate oscillator of a symbol.
+// The three time periods default to 7, 14 and 28 and can be changed
+// with WithTimePeriod1, WithTimePeriod2 and WithTimePeriod3.
+// Data is returned from past to present.
+func (c *Client) ULTOSC(ctx context.Context, symbol string, interval TimeInterval, opts ...IndicatorOption) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueULTOSCEndpoint, symbol, interval, indicatorParams(map[string]string{
+		queryTimePeriod1: "7",
+		queryTimePeriod2: "14",
+		queryTimePeriod3: "28",
+	}, opts))
+}
+
+// WILLR queries the Williams' %R of a symbol.
+// Data is returned from past to present.
+func (c *Client) WILLR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueWILLREndpoint, symbol, interval, timePeriod)
+}
+
+// STOCH queries the stochastic oscillator of a symbol.
+// The fastk, slowk and slowd periods default to 5, 3 and 3 and can be changed
+// with WithFastKPeriod, WithSlowKPeriod and WithSlowDPeriod.
+// The moving average types default to MATypeSMA and can be changed
+// with WithSlowKMAType and WithSlowDMAType.
+// Data is returned from past to present.
+func (c *Client) STOCH(ctx context.Context, symbol string, interval TimeInterval, opts ...IndicatorOption) ([]*STOCHValue, error) {
+	return c.stoch(ctx, symbol, interval, indicatorParams(map[string]string{
+		queryFastKPeriod: "5",
+		querySlowKPeriod: "3",
+		querySlowDPeriod: "3",
+		querySlowKMAType: "0",
+		querySlowDMAType: "0",
+	}, opts))
+}
+
+// STOCHFValue is a piece of data for a given time about a fast stochastic oscillator
+type STOCHFValue struct {
+	Time  time.Time
+	FastK float64
+	FastD float64
+}
+
+// STOCHF queries the fast stochastic oscillator of a symbol.
+// The fastk and fastd periods default to 5 and 3 and can be changed
+// with WithFastKPeriod and WithFastDPeriod.
+// The moving average type defaults to MATypeSMA and can be changed with WithFastDMAType.
+// Data is returned from past to present.
+func (c *Client) STOCHF(ctx context.Context, symbol string, interval TimeInterval, opts ...IndicatorOption) ([]*STOCHFValue, error) {
+	return c.stochf(ctx, valueSTOCHFEndpoint, symbol, interval, indicatorParams(map[string]string{
+		queryFastKPeriod: "5",
+		queryFastDPeriod: "3",
+		queryFastDMAType: "0",
+	}, opts))
+}
+
+// STOCHRSI queries the stochastic relative strength index of a symbol.
+// The fastk and fastd periods default to 5 and 3 and can be changed
+// with WithFastKPeriod and WithFastDPeriod.
+// The moving average type defaults to MATypeSMA and can be changed with WithFastDMAType.
+// Data is returned from past to present.
+func (c *Client) STOCHRSI(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType, opts ...IndicatorOption) ([]*STOCHFValue, error) {
+	params := indicatorParams(map[string]string{
+		queryFastKPeriod: "5",
+		queryFastDPeriod: "3",
+		queryFastDMAType: "0",
+	}, opts)
+	params[queryTimePeriod] = strconv.Itoa(timePeriod)
+	params[querySeriesType] = seriesType.keyName()
+
+	return c.stochf(ctx, valueSTOCHRSIEndpoint, symbol, interval, params)
+}
+
+// stochf queries a fast stochastic indicator and maps its columns to STOCHFValue
+func (c *Client) stochf(ctx context.Context, function string, symbol string, interval TimeInterval, params map[string]string) ([]*STOCHFValue, error) {
+	values, err := c.technicalIndicator(ctx, function, symbol, interval, params)
+	if err != nil {
+		return nil, err
+	}
+
+	stochf := make([]*STOCHFValue, 0, len(values))
+	for _, value := range values {
+		v, err := value.lookup(columnFastK, columnFastD)
+		if err != nil {
+			return nil, err
+		}
+		stochf = append(stochf, &STOCHFValue{
+			Time:  value.Time,
+			FastK: v[0],
+			FastD: v[1],
+		})
+	}
+	return stochf, nil
+}
@@ -0,0 +1,727 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_MACD_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			expected: "query?apikey=test&datatype=csv&function=MACD&interval=daily&outputsize=compact&series_type=close&symbol=TEST",
+		},
+		{
+			desc:     "default values",
+			opts:     []IndicatorOption{WithFastPeriod(12), WithSlowPeriod(26), WithSignalPeriod(9)},
+			expected: "query?apikey=test&datatype=csv&function=MACD&interval=daily&outputsize=compact&series_type=close&symbol=TEST",
+		},
+		{
+			desc:     "overrides",
+			opts:     []IndicatorOption{WithFastPeriod(8), WithSlowPeriod(17), WithSignalPeriod(5)},
+			expected: "query?apikey=test&datatype=csv&fastperiod=8&function=MACD&interval=daily&outputsize=compact&series_type=close&signalperiod=5&slowperiod=17&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleMACDData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.MACD(context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose, tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_MACD_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMACDData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.MACD(context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	for i := 1; i < len(result); i++ {
+		if !result[i-1].Time.Before(result[i].Time) {
+			t.Fatalf("results are not sorted from past to present")
+		}
+	}
+	last := result[len(result)-1]
+	if last.MACD != 5.2311 || last.Hist != -0.4120 || last.Signal != 5.6431 {
+		t.Errorf("unexpected last value, got %+v", last)
+	}
+}
+
+func TestClient_MACDEXT_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			opts:     []IndicatorOption{WithFastMAType(MATypeSMA), WithSlowMAType(MATypeSMA), WithSignalMAType(MATypeSMA)},
+			expected: "query?apikey=test&datatype=csv&function=MACDEXT&interval=daily&outputsize=compact&series_type=close&symbol=TEST",
+		},
+		{
+			desc:     "ma types",
+			opts:     []IndicatorOption{WithFastMAType(MATypeEMA), WithSlowMAType(MATypeKAMA), WithSignalMAType(MATypeMAMA)},
+			expected: "query?apikey=test&datatype=csv&fastmatype=1&function=MACDEXT&interval=daily&outputsize=compact&series_type=close&signalmatype=8&slowmatype=7&symbol=TEST",
+		},
+		{
+			desc:     "periods",
+			opts:     []IndicatorOption{WithFastPeriod(10), WithSignalMAType(MATypeT3)},
+			expected: "query?apikey=test&datatype=csv&fastperiod=10&function=MACDEXT&interval=daily&outputsize=compact&series_type=close&signalmatype=6&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleMACDData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.MACDEXT(context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if len(result) != 3 {
+				t.Errorf("unexpected result count, want 3 got %d", len(result))
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_MACD_ignoresOtherOptions(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=MACD&interval=daily&outputsize=compact&series_type=close&symbol=TEST"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMACDData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	_, _ = client.MACD(context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose, WithFastMAType(MATypeEMA))
+
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+}
+
+func TestClient_ADX(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=ADX&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleADXData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.ADX(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 23.5108 {
+		t.Errorf("unexpected value, want 23.5108 got %f", v)
+	}
+}
+
+func TestClient_ADXR(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=ADXR&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleADXRData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.ADXR(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 20.1432 {
+		t.Errorf("unexpected value, want 20.1432 got %f", v)
+	}
+}
+
+func TestClient_priceOscillator(t *testing.T) {
+	tests := []struct {
+		desc     string
+		method   func(c *Client, ctx context.Context, symbol string, interval TimeInterval, seriesType SeriesType, opts ...IndicatorOption) ([]*IndicatorValue, error)
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "APO defaults",
+			method:   (*Client).APO,
+			expected: "query?apikey=test&datatype=csv&function=APO&interval=daily&outputsize=compact&series_type=close&symbol=TEST",
+		},
+		{
+			desc:     "APO default values",
+			method:   (*Client).APO,
+			opts:     []IndicatorOption{WithFastPeriod(12), WithSlowPeriod(26), WithMAType(MATypeSMA)},
+			expected: "query?apikey=test&datatype=csv&function=APO&interval=daily&outputsize=compact&series_type=close&symbol=TEST",
+		},
+		{
+			desc:     "PPO defaults",
+			method:   (*Client).PPO,
+			expected: "query?apikey=test&datatype=csv&function=PPO&interval=daily&outputsize=compact&series_type=close&symbol=TEST",
+		},
+		{
+			desc:     "PPO overrides",
+			method:   (*Client).PPO,
+			opts:     []IndicatorOption{WithFastPeriod(10), WithSlowPeriod(21), WithMAType(MATypeEMA)},
+			expected: "query?apikey=test&datatype=csv&fastperiod=10&function=PPO&interval=daily&matype=1&outputsize=compact&series_type=close&slowperiod=21&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(samplePriceOscillatorData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := tt.method(client, context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != 1.8211 {
+				t.Errorf("unexpected value, want 1.8211 got %f", v)
+			}
+		})
+	}
+}
+
+func TestClient_AROON(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=AROON&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleAROONData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.AROON(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	// columns are mapped by name, Aroon Down comes first in the data
+	last := result[len(result)-1]
+	if last.AroonUp != 100 || last.AroonDown != 7.1429 {
+		t.Errorf("unexpected value, want up 100 down 7.1429 got up %f down %f", last.AroonUp, last.AroonDown)
+	}
+}
+
+func TestClient_AROONOSC(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=AROONOSC&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleAROONOSCData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.AROONOSC(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 92.8571 {
+		t.Errorf("unexpected value, want 92.8571 got %f", v)
+	}
+}
+
+func TestClient_BOP(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=BOP&interval=daily&outputsize=compact&symbol=TEST"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleBOPData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.BOP(context.Background(), "TEST", TimeIntervalDaily)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 0.3261 {
+		t.Errorf("unexpected value, want 0.3261 got %f", v)
+	}
+}
+
+func TestClient_CMO(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=CMO&interval=daily&outputsize=compact&series_type=close&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleCMOData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.CMO(context.Background(), "TEST", TimeIntervalDaily, 14, SeriesTypeClose)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 4 {
+		t.Fatalf("unexpected result count, want 4 got %d", len(result))
+	}
+	for _, value := range result {
+		if v := value.Value(); v < -100 || v > 100 {
+			t.Errorf("cmo out of range at %s: %f", value.Time, v)
+		}
+	}
+	if v := result[len(result)-1].Value(); v != -42.1873 {
+		t.Errorf("unexpected value, want -42.1873 got %f", v)
+	}
+}
+
+func TestClient_CCI(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=CCI&interval=daily&outputsize=compact&symbol=TEST&time_period=20"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleCCIData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.CCI(context.Background(), "TEST", TimeIntervalDaily, 20)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 5 {
+		t.Fatalf("unexpected result count, want 5 got %d", len(result))
+	}
+	// cci is unbounded, values are past to present
+	if v := result[0].Value(); v != 236.4788 {
+		t.Errorf("unexpected value, want 236.4788 got %f", v)
+	}
+	if v := result[len(result)-1].Value(); v != -184.3312 {
+		t.Errorf("unexpected value, want -184.3312 got %f", v)
+	}
+}
+
+func TestClient_MFI(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=MFI&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMFIData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.MFI(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 61.229 {
+		t.Errorf("unexpected value, want 61.229 got %f", v)
+	}
+}
+
+func TestClient_seriesMomentum(t *testing.T) {
+	tests := []struct {
+		function string
+		method   func(c *Client, ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error)
+		data     string
+		last     float64
+	}{
+		{function: "MOM", method: (*Client).MOM, data: sampleMOMData, last: 4.12},
+		{function: "ROC", method: (*Client).ROC, data: sampleROCData, last: 2.4734},
+		{function: "ROCR", method: (*Client).ROCR, data: sampleROCRData, last: 1.0247},
+		{function: "TRIX", method: (*Client).TRIX, data: sampleTRIXData, last: 0.1342},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			expected := "query?apikey=test&datatype=csv&function=" + tt.function + "&interval=daily&outputsize=compact&series_type=open&symbol=TEST&time_period=10"
+			res := &http.Response{
+				Body:       NewBuffCloser(tt.data),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := tt.method(client, context.Background(), "TEST", TimeIntervalDaily, 10, SeriesTypeOpen)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != expected {
+				t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != tt.last {
+				t.Errorf("unexpected value, want %f got %f", tt.last, v)
+			}
+		})
+	}
+}
+
+func TestClient_RSI(t *testing.T) {
+	tests := []struct {
+		interval TimeInterval
+		expected string
+	}{
+		{
+			interval: TimeIntervalOneMinute,
+			expected: "query?apikey=test&datatype=csv&function=RSI&interval=1min&outputsize=compact&series_type=close&symbol=TEST&time_period=14",
+		},
+		{
+			interval: TimeIntervalDaily,
+			expected: "query?apikey=test&datatype=csv&function=RSI&interval=daily&outputsize=compact&series_type=close&symbol=TEST&time_period=14",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interval.String(), func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleRSIData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.RSI(context.Background(), "TEST", tt.interval, 14, SeriesTypeClose)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 5 {
+				t.Fatalf("unexpected result count, want 5 got %d", len(result))
+			}
+			for _, value := range result {
+				if v := value.Value(); v < 0 || v > 100 {
+					t.Errorf("rsi out of range at %s: %f", value.Time, v)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_ULTOSC(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			expected: "query?apikey=test&datatype=csv&function=ULTOSC&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "default values",
+			opts:     []IndicatorOption{WithTimePeriod1(7), WithTimePeriod2(14), WithTimePeriod3(28)},
+			expected: "query?apikey=test&datatype=csv&function=ULTOSC&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "overrides",
+			opts:     []IndicatorOption{WithTimePeriod1(5), WithTimePeriod2(10), WithTimePeriod3(20)},
+			expected: "query?apikey=test&datatype=csv&function=ULTOSC&interval=daily&outputsize=compact&symbol=TEST&timeperiod1=5&timeperiod2=10&timeperiod3=20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleULTOSCData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.ULTOSC(context.Background(), "TEST", TimeIntervalDaily, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != 58.3917 {
+				t.Errorf("unexpected value, want 58.3917 got %f", v)
+			}
+		})
+	}
+}
+
+func TestClient_WILLR(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=WILLR&interval=weekly&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleWILLRData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.WILLR(context.Background(), "TEST", TimeIntervalWeekly, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 5 {
+		t.Fatalf("unexpected result count, want 5 got %d", len(result))
+	}
+	for _, value := range result {
+		if v := value.Value(); v < -100 || v > 0 {
+			t.Errorf("willr out of range at %s: %f", value.Time, v)
+		}
+	}
+	if v := result[len(result)-1].Value(); v != -100 {
+		t.Errorf("unexpected value, want -100 got %f", v)
+	}
+}
+
+func TestClient_STOCH_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			expected: "query?apikey=test&datatype=csv&function=STOCH&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc: "default values",
+			opts: []IndicatorOption{
+				WithFastKPeriod(5), WithSlowKPeriod(3), WithSlowDPeriod(3),
+				WithSlowKMAType(MATypeSMA), WithSlowDMAType(MATypeSMA),
+			},
+			expected: "query?apikey=test&datatype=csv&function=STOCH&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc: "overrides",
+			opts: []IndicatorOption{
+				WithFastKPeriod(14), WithSlowKPeriod(5), WithSlowDPeriod(5),
+				WithSlowKMAType(MATypeEMA), WithSlowDMAType(MATypeWMA),
+			},
+			expected: "query?apikey=test&datatype=csv&fastkperiod=14&function=STOCH&interval=daily&outputsize=compact&slowdmatype=2&slowdperiod=5&slowkmatype=1&slowkperiod=5&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleSTOCHData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.STOCH(context.Background(), "TEST", TimeIntervalDaily, tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_STOCH_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleSTOCHData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.STOCH(context.Background(), "TEST", TimeIntervalFifteenMinute)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 4 {
+		t.Fatalf("unexpected result count, want 4 got %d", len(result))
+	}
+	first := result[0]
+	if first.SlowK != 64.9694 || first.SlowD != 66.4127 {
+		t.Errorf("unexpected first value, got %+v", first)
+	}
+}
+
+func TestClient_STOCHF_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			opts:     []IndicatorOption{WithFastKPeriod(5), WithFastDPeriod(3)},
+			expected: "query?apikey=test&datatype=csv&function=STOCHF&interval=weekly&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "overrides",
+			opts:     []IndicatorOption{WithFastKPeriod(10), WithFastDPeriod(4), WithFastDMAType(MATypeTEMA)},
+			expected: "query?apikey=test&datatype=csv&fastdmatype=4&fastdperiod=4&fastkperiod=10&function=STOCHF&interval=weekly&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleSTOCHFData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.STOCHF(context.Background(), "TEST", TimeIntervalWeekly, tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_STOCHF_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleSTOCHFData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.STOCHF(context.Background(), "TEST", TimeIntervalWeekly)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	last := result[len(result)-1]
+	if last.FastK != 12.4587 || last.FastD != 35.7812 {
+		t.Errorf("unexpected last value, got %+v", last)
+	}
+}
+
+func TestClient_STOCHRSI(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&fastdmatype=1&fastdperiod=5&fastkperiod=10&function=STOCHRSI&interval=daily&outputsize=compact&series_type=high&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleSTOCHFData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.STOCHRSI(context.Background(), "TEST", TimeIntervalDaily, 14, SeriesTypeHigh,
+		WithFastKPeriod(10), WithFastDPeriod(5), WithFastDMAType(MATypeEMA))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	first := result[0]
+	if first.FastK != 53.8586 || first.FastD != 60.1204 {
+		t.Errorf("unexpected first value, got %+v", first)
+	}
+}
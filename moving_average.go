@@ -0,0 +1,155 @@
+package av
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	queryFastLimit = "fastlimit"
+	querySlowLimit = "slowlimit"
+
+	valueSMAEndpoint = "SMA"
+	valueEMAEndpoint = "EMA"
+
+	columnMAMA = "MAMA"
+	columnFAMA = "FAMA"
+
+	// mamaLimitDefault is the Alpha Vantage default of both MAMA limits
+	mamaLimitDefault = 0.01
+)
+
+// ErrInvalidMAType is returned when a moving average type can not be queried
+var ErrInvalidMAType = errors.New("invalid moving average type")
+
+// MAType specifies a type of moving average.
+// For valid options, see the MAType* package constants.
+//
+// The values match the moving average type codes used by Alpha Vantage.
+type MAType uint8
+
+const (
+	MATypeSMA MAType = iota
+	MATypeEMA
+	MATypeWMA
+	MATypeDEMA
+	MATypeTEMA
+	MATypeTRIMA
+	MATypeT3
+	MATypeKAMA
+	MATypeMAMA
+)
+
+func (t MAType) String() string {
+	switch t {
+	case MATypeSMA:
+		return "MATypeSMA"
+	case MATypeEMA:
+		return "MATypeEMA"
+	case MATypeWMA:
+		return "MATypeWMA"
+	case MATypeDEMA:
+		return "MATypeDEMA"
+	case MATypeTEMA:
+		return "MATypeTEMA"
+	case MATypeTRIMA:
+		return "MATypeTRIMA"
+	case MATypeT3:
+		return "MATypeT3"
+	case MATypeKAMA:
+		return "MATypeKAMA"
+	case MATypeMAMA:
+		return "MATypeMAMA"
+	}
+	return "MATypeUnknown"
+}
+
+// keyName returns the name of the MAType function used for Alpha Vantage API
+func (t MAType) keyName() string {
+	switch t {
+	case MATypeSMA:
+		return "SMA"
+	case MATypeEMA:
+		return "EMA"
+	case MATypeWMA:
+		return "WMA"
+	case MATypeDEMA:
+		return "DEMA"
+	case MATypeTEMA:
+		return "TEMA"
+	case MATypeTRIMA:
+		return "TRIMA"
+	case MATypeT3:
+		return "T3"
+	case MATypeKAMA:
+		return "KAMA"
+	case MATypeMAMA:
+		return "MAMA"
+	}
+	return "UNKNOWN"
+}
+
+// SMA queries the simple moving average of a symbol.
+// Data is returned from past to present.
+func (c *Client) SMA(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueSMAEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// EMA queries the exponential moving average of a symbol.
+// Data is returned from past to present.
+func (c *Client) EMA(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueEMAEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// MovingAverage queries a moving average of a symbol, i.e. MATypeWMA or MATypeKAMA.
+// MATypeMAMA takes different parameters and can not be queried with this method.
+// Data is returned from past to present.
+func (c *Client) MovingAverage(ctx context.Context, maType MAType, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	if maType >= MATypeMAMA {
+		return nil, ErrInvalidMAType
+	}
+	return c.seriesIndicator(ctx, maType.keyName(), symbol, interval, timePeriod, seriesType)
+}
+
+// MAMAValue is a piece of data for a given time about the MESA adaptive moving average
+type MAMAValue struct {
+	Time time.Time
+	MAMA float64
+	FAMA float64
+}
+
+// MAMA queries the MESA adaptive moving average of a symbol.
+// A zero fastLimit or slowLimit uses the Alpha Vantage default of 0.01.
+// Data is returned from past to present.
+func (c *Client) MAMA(ctx context.Context, symbol string, interval TimeInterval, seriesType SeriesType, fastLimit float64, slowLimit float64) ([]*MAMAValue, error) {
+	if fastLimit == 0 {
+		fastLimit = mamaLimitDefault
+	}
+	if slowLimit == 0 {
+		slowLimit = mamaLimitDefault
+	}
+	values, err := c.technicalIndicator(ctx, MATypeMAMA.keyName(), symbol, interval, map[string]string{
+		querySeriesType: seriesType.keyName(),
+		queryFastLimit:  formatFloat(fastLimit),
+		querySlowLimit:  formatFloat(slowLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mama := make([]*MAMAValue, 0, len(values))
+	for _, value := range values {
+		v, err := value.lookup(columnMAMA, columnFAMA)
+		if err != nil {
+			return nil, err
+		}
+		mama = append(mama, &MAMAValue{
+			Time: value.Time,
+			MAMA: v[0],
+			FAMA: v[1],
+		})
+	}
+	return mama, nil
+}
@@ -0,0 +1,203 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_SMA_buildsUrl(t *testing.T) {
+	tests := []struct {
+		interval   TimeInterval
+		seriesType SeriesType
+		expected   string
+	}{
+		{
+			interval:   TimeIntervalFifteenMinute,
+			seriesType: SeriesTypeClose,
+			expected:   "query?apikey=test&datatype=csv&function=SMA&interval=15min&outputsize=compact&series_type=close&symbol=TEST&time_period=10",
+		},
+		{
+			interval:   TimeIntervalWeekly,
+			seriesType: SeriesTypeOpen,
+			expected:   "query?apikey=test&datatype=csv&function=SMA&interval=weekly&outputsize=compact&series_type=open&symbol=TEST&time_period=10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interval.String(), func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleSMAData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.SMA(context.Background(), "TEST", tt.interval, 10, tt.seriesType)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_SMA_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleSMAData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.SMA(context.Background(), "TEST", TimeIntervalWeekly, 10, SeriesTypeOpen)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[0].Value(); v != 402.1130 {
+		t.Errorf("unexpected first value, want 402.1130 got %f", v)
+	}
+	if v := result[len(result)-1].Value(); v != 407.8810 {
+		t.Errorf("unexpected last value, want 407.8810 got %f", v)
+	}
+}
+
+func TestClient_EMA(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=EMA&interval=daily&outputsize=compact&series_type=high&symbol=TEST&time_period=200"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleEMAData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.EMA(context.Background(), "TEST", TimeIntervalDaily, 200, SeriesTypeHigh)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if tp := conn.endpoint.Query().Get("time_period"); tp != "200" {
+		t.Errorf("unexpected time period, want 200 got %s", tp)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 406.2214 {
+		t.Errorf("unexpected last value, want 406.2214 got %f", v)
+	}
+}
+
+func TestClient_MovingAverage_buildsUrl(t *testing.T) {
+	for maType := MATypeSMA; maType < MATypeMAMA; maType++ {
+		t.Run(maType.String(), func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleSMAData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.MovingAverage(context.Background(), maType, "TEST", TimeIntervalDaily, 10, SeriesTypeClose)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if len(result) != 3 {
+				t.Errorf("unexpected result count, want 3 got %d", len(result))
+			}
+
+			expected := "query?apikey=test&datatype=csv&function=" + maType.keyName() + "&interval=daily&outputsize=compact&series_type=close&symbol=TEST&time_period=10"
+			if conn.endpoint.String() != expected {
+				t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestMAType_keyName(t *testing.T) {
+	expected := []string{"SMA", "EMA", "WMA", "DEMA", "TEMA", "TRIMA", "T3", "KAMA", "MAMA"}
+	for i, name := range expected {
+		if got := MAType(i).keyName(); got != name {
+			t.Errorf("unexpected function for %s, want %s got %s", MAType(i), name, got)
+		}
+	}
+}
+
+func TestClient_MovingAverage_invalidType(t *testing.T) {
+	for _, maType := range []MAType{MATypeMAMA, MATypeMAMA + 1} {
+		conn := NewErrorConnection(nil)
+		client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+		_, err := client.MovingAverage(context.Background(), maType, "TEST", TimeIntervalDaily, 10, SeriesTypeClose)
+		if err != ErrInvalidMAType {
+			t.Errorf("unexpected error for %s, want %v got %v", maType, ErrInvalidMAType, err)
+		}
+		if conn.endpoint != nil {
+			t.Errorf("request was made for %s", maType)
+		}
+	}
+}
+
+func TestClient_MAMA_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc      string
+		fastLimit float64
+		slowLimit float64
+		expected  string
+	}{
+		{
+			desc:     "defaults",
+			expected: "query?apikey=test&datatype=csv&fastlimit=0.01&function=MAMA&interval=daily&outputsize=compact&series_type=close&slowlimit=0.01&symbol=TEST",
+		},
+		{
+			desc:      "small limits",
+			fastLimit: 0.00002,
+			slowLimit: 0.5,
+			expected:  "query?apikey=test&datatype=csv&fastlimit=0.00002&function=MAMA&interval=daily&outputsize=compact&series_type=close&slowlimit=0.5&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleMAMAData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.MAMA(context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose, tt.fastLimit, tt.slowLimit)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_MAMA_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMAMAData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.MAMA(context.Background(), "TEST", TimeIntervalDaily, SeriesTypeClose, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	last := result[len(result)-1]
+	if last.MAMA != 408.3871 || last.FAMA != 401.7734 {
+		t.Errorf("unexpected last value, got %+v", last)
+	}
+}
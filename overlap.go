@@ -0,0 +1,40 @@
+package av
+
+import (
+	"context"
+)
+
+const (
+	queryAcceleration = "acceleration"
+	queryMaximum      = "maximum"
+
+	valueSAREndpoint      = "SAR"
+	valueMIDPOINTEndpoint = "MIDPOINT"
+	valueMIDPRICEEndpoint = "MIDPRICE"
+)
+
+// SAR queries the parabolic stop and reverse of a symbol.
+// The acceleration factor and its maximum default to 0.01 and 0.2 and can be
+// changed with WithAcceleration and WithMaximum.
+// Data is returned from past to present.
+func (c *Client) SAR(ctx context.Context, symbol string, interval TimeInterval, opts ...IndicatorOption) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueSAREndpoint, symbol, interval, indicatorParams(map[string]string{
+		queryAcceleration: formatFloat(0.01),
+		queryMaximum:      formatFloat(0.2),
+	}, opts))
+}
+
+// MIDPOINT queries the midpoint of the highest and lowest value of a price series
+// over a time period of a symbol. Unlike MIDPRICE, it is calculated from a single price series.
+// Data is returned from past to present.
+func (c *Client) MIDPOINT(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.seriesIndicator(ctx, valueMIDPOINTEndpoint, symbol, interval, timePeriod, seriesType)
+}
+
+// MIDPRICE queries the midpoint of the highest high and lowest low price
+// over a time period of a symbol. Unlike MIDPOINT, it is calculated from the
+// high and low prices, so it takes no series type.
+// Data is returned from past to present.
+func (c *Client) MIDPRICE(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueMIDPRICEEndpoint, symbol, interval, timePeriod)
+}
@@ -0,0 +1,107 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_SAR(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			expected: "query?apikey=test&datatype=csv&function=SAR&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "default values",
+			opts:     []IndicatorOption{WithAcceleration(0.01), WithMaximum(0.20)},
+			expected: "query?apikey=test&datatype=csv&function=SAR&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "overrides",
+			opts:     []IndicatorOption{WithAcceleration(0.00001), WithMaximum(0.5)},
+			expected: "query?acceleration=0.00001&apikey=test&datatype=csv&function=SAR&interval=daily&maximum=0.5&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleSARData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.SAR(context.Background(), "TEST", TimeIntervalDaily, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != 168.49 {
+				t.Errorf("unexpected value, want 168.49 got %f", v)
+			}
+		})
+	}
+}
+
+func TestClient_MIDPOINT(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=MIDPOINT&interval=daily&outputsize=compact&series_type=high&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMIDPOINTData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.MIDPOINT(context.Background(), "TEST", TimeIntervalDaily, 14, SeriesTypeHigh)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 171.235 {
+		t.Errorf("unexpected value, want 171.235 got %f", v)
+	}
+}
+
+func TestClient_MIDPRICE(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=MIDPRICE&interval=daily&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMIDPRICEData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.MIDPRICE(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 171.985 {
+		t.Errorf("unexpected value, want 171.985 got %f", v)
+	}
+}
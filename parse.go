@@ -1,18 +1,49 @@
 package av
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
+// errMissingValue is the cause of a RowError when a row is shorter than its header
+var errMissingValue = errors.New("missing value")
+
+// RowError is an error parsing a value of a csv row
+type RowError struct {
+	// Row is the number of the row, starting at 1 for the first row after the header
+	Row int
+	// Column is the name of the column of the value
+	Column string
+	// Value is the value which could not be parsed
+	Value string
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: error parsing %s %q: %v", e.Row, e.Column, e.Value, e.Err)
+}
+
+// Cause returns the cause of the error
+func (e *RowError) Cause() error {
+	return e.Err
+}
+
 // parseFloat parses a float value.
 // An error is returned if the value is not a float value.
 func parseFloat(val string) (float64, error) {
 	return strconv.ParseFloat(val, 64)
 }
 
+// formatFloat formats a float value for a query parameter.
+// The value is never formatted with an exponent.
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
 // parseInt parses an int value.
 // An error is returned if the value is not an int value.
 func parseInt(val string) (int, error) {
@@ -1,8 +1,11 @@
 package av
 
 import (
+	"context"
 	"math"
+	"math/rand"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -11,38 +14,105 @@ import (
 
 const (
 	DefaultDayLimit    = math.MaxInt32
+	DefaultMinuteLimit = math.MaxInt32
 	DefaultSecondLimit = math.MaxInt32
+
+	// defaultSleepStep is how long Do sleeps between checks of the per-minute limit
+	defaultSleepStep = 50 * time.Millisecond
+)
+
+const (
+	// tradingDayLocation is the time zone of the trading day,
+	// in which Alpha Vantage also resets daily API limits
+	tradingDayLocation = "America/New_York"
+	tradingDayFormat   = "2006-01-02"
 )
 
 var ErrDailyLimitReached = errors.New("daily API limit has been reached")
 
-// RateLimiter limits the per-second and per-day execution counts.
+// RateLimiter limits the per-second, per-minute and per-day execution counts.
 //
 // It delays execution to comply with API restrictions (i.e. 5 calls per second).
+// Calls are spaced evenly within a second, so no more than the per-second limit
+// are executed within any one second window. The per-day count resets at midnight
+// US/Eastern time, like the daily limit of the API.
+//
+// A RateLimiter is safe for concurrent use. Clients sharing an API key, and therefore
+// its quota, should share a single RateLimiter by passing it to each of their
+// connections with WithRateLimiter.
 //
 // Usage
-// 	rl := NewRateLimiter(500, 5) // 500 calls per day, 5 calls per second
+//
+//	rl := NewRateLimiter(500, 5) // 500 calls per day, 5 calls per second
+//	rl.Do(funcToExecute())
+//
+//	rl := NewRateLimiterPerMinute(0, 1200, 0) // 1200 calls per minute
 //	rl.Do(funcToExecute())
+//
+//	rl := NewRateLimiter(500, 5) // shared by the stock and crypto clients
+//	stocks := NewClient(WithAPIKey(key), WithConnection(NewConnection(WithRateLimiter(rl))))
+//	crypto := NewClient(WithAPIKey(key), WithConnection(NewConnection(WithRateLimiter(rl))))
 type RateLimiter struct {
+	clock  Clock
+	loc    *time.Location
+	step   time.Duration
+	jitter time.Duration
+
 	secLimit int32
 	secCount int32
+	sec      *tokenBucket
+	minLimit int32
+	minCount int32
 	dayLimit int32
 	dayCount int32
+
+	// dayReset is closed when the per day count is reset
+	mu       sync.Mutex
+	dayReset chan struct{}
+
+	// done is closed to stop resetting the counts
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-func NewRateLimiter(dayLimit int, secLimit int) *RateLimiter {
+// NewRateLimiter creates a RateLimiter with per-day and per-second limits.
+// A zero limit is unlimited.
+func NewRateLimiter(dayLimit int, secLimit int, opts ...RateLimiterOption) *RateLimiter {
+	return NewRateLimiterPerMinute(dayLimit, 0, secLimit, opts...)
+}
+
+// NewRateLimiterPerMinute creates a RateLimiter with per-day, per-minute and per-second limits.
+// A zero limit is unlimited.
+func NewRateLimiterPerMinute(dayLimit int, minLimit int, secLimit int, opts ...RateLimiterOption) *RateLimiter {
+	o := defaultRateLimiterOptions()
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
 	if dayLimit == 0 {
 		dayLimit = DefaultDayLimit
 	}
+	if minLimit == 0 {
+		minLimit = DefaultMinuteLimit
+	}
 	if secLimit == 0 {
-		dayLimit = DefaultSecondLimit
+		secLimit = DefaultSecondLimit
 	}
 
 	l := &RateLimiter{
+		clock:    o.clock,
+		loc:      loadTradingDayLocation(),
+		step:     o.step,
+		jitter:   o.jitter,
 		secLimit: int32(secLimit),
 		secCount: 0,
+		sec:      newTokenBucket(o.clock, time.Second/time.Duration(secLimit)),
+		minLimit: int32(minLimit),
+		minCount: 0,
 		dayLimit: int32(dayLimit),
 		dayCount: 0,
+		dayReset: make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 
 	l.init()
@@ -51,41 +121,219 @@ func NewRateLimiter(dayLimit int, secLimit int) *RateLimiter {
 }
 
 func (l *RateLimiter) init() {
-	secTicker := time.NewTicker(time.Second)
-	dayTicker := time.NewTicker(24 * time.Hour)
+	secTicker := l.clock.NewTicker(time.Second)
+	minTicker := l.clock.NewTicker(time.Minute)
+	day := l.today()
 
 	go func() {
+		defer secTicker.Stop()
+		defer minTicker.Stop()
+
 		for {
 			select {
-			case <-secTicker.C:
+			case <-l.done:
+				return
+			case <-secTicker.C():
 				// Reset the current per second count.
 				atomic.StoreInt32(&l.secCount, 0)
-			case <-dayTicker.C:
-				// Reset the current per day count.
-				atomic.StoreInt32(&l.dayCount, 0)
+				// Reset the current per day count once the day changes, at midnight.
+				if today := l.today(); today != day {
+					day = today
+					l.resetDay()
+				}
+			case <-minTicker.C():
+				// Reset the current per minute count.
+				atomic.StoreInt32(&l.minCount, 0)
 			}
 		}
 	}()
 }
 
+// Close stops the RateLimiter from resetting its counts and releases its resources.
+// The RateLimiter must not be used after it is closed. Close is safe to call more than once.
+func (l *RateLimiter) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+// today returns the current day in the time zone of the daily limit
+func (l *RateLimiter) today() string {
+	return l.clock.Now().In(l.loc).Format(tradingDayFormat)
+}
+
+// loadTradingDayLocation returns the time zone of the trading day
+func loadTradingDayLocation() *time.Location {
+	loc, err := time.LoadLocation(tradingDayLocation)
+	if err != nil {
+		// the time zone database is missing, standard time is close enough
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
+// resetDay resets the current per day count and wakes up any waiting executions
+func (l *RateLimiter) resetDay() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	atomic.StoreInt32(&l.dayCount, 0)
+	close(l.dayReset)
+	l.dayReset = make(chan struct{})
+}
+
+// dayResetC returns a channel which is closed when the per day count is reset
+func (l *RateLimiter) dayResetC() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.dayReset
+}
+
 // Do executes the given function.
 //
-// It will delays execution by 50ms steps if the per-second
-// limit has been reached.
+// It will delays execution until a per-second token is available,
+// and by 50ms steps if the per-minute limit has been reached.
+// The steps can be changed with WithSleepStep and WithJitter.
 func (l *RateLimiter) Do(f func() (*http.Response, error)) (*http.Response, error) {
-	if atomic.LoadInt32(&l.dayCount) == l.dayLimit {
+	return l.do(context.Background(), f)
+}
+
+// do executes the given function like Do. If the context is done while execution
+// is delayed, the acquired counts are released and the error of the context is returned.
+func (l *RateLimiter) do(ctx context.Context, f func() (*http.Response, error)) (*http.Response, error) {
+	if !acquire(&l.dayCount, l.dayLimit) {
 		return nil, ErrDailyLimitReached
 	}
 
 	// Delay until the count is reset.
-	for atomic.LoadInt32(&l.secCount) == l.secLimit {
-		time.Sleep(50 * time.Millisecond)
+	for !acquire(&l.minCount, l.minLimit) {
+		select {
+		case <-l.clock.After(l.sleepStep()):
+		case <-ctx.Done():
+			release(&l.dayCount)
+			return nil, ctx.Err()
+		}
+	}
+
+	// Delay until a token is available.
+	if wait := l.sec.reserve(); wait > 0 {
+		select {
+		case <-l.clock.After(wait):
+		case <-ctx.Done():
+			release(&l.dayCount)
+			release(&l.minCount)
+			return nil, ctx.Err()
+		}
 	}
 
 	// Execute function and increment count.
-	res, err := f()
 	atomic.AddInt32(&l.secCount, 1)
-	atomic.AddInt32(&l.dayCount, 1)
+	return f()
+}
+
+// sleepStep returns how long to sleep before checking the per-minute limit again
+func (l *RateLimiter) sleepStep() time.Duration {
+	if l.jitter <= 0 {
+		return l.step
+	}
+	return l.step + time.Duration(rand.Int63n(int64(l.jitter)))
+}
+
+// acquire increments count if it is below limit.
+// It reports whether the count was incremented.
+func acquire(count *int32, limit int32) bool {
+	for {
+		c := atomic.LoadInt32(count)
+		if c >= limit {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(count, c, c+1) {
+			return true
+		}
+	}
+}
+
+// release decrements count if it is above zero, undoing an acquire
+// unless the count was reset in the meantime.
+func release(count *int32) {
+	for {
+		c := atomic.LoadInt32(count)
+		if c <= 0 || atomic.CompareAndSwapInt32(count, c, c-1) {
+			return
+		}
+	}
+}
+
+// DoWait executes the given function like Do.
+//
+// Instead of returning ErrDailyLimitReached, it blocks until the per day
+// count is reset at midnight US/Eastern time or the context is done.
+func (l *RateLimiter) DoWait(ctx context.Context, f func() (*http.Response, error)) (*http.Response, error) {
+	for {
+		reset := l.dayResetC()
+		res, err := l.do(ctx, f)
+		if err != ErrDailyLimitReached {
+			return res, err
+		}
+
+		select {
+		case <-reset:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Used returns the number of executions in the current day and second.
+func (l *RateLimiter) Used() (day int, sec int) {
+	return int(atomic.LoadInt32(&l.dayCount)), int(atomic.LoadInt32(&l.secCount))
+}
+
+// Remaining returns the number of executions left in the current day and second.
+func (l *RateLimiter) Remaining() (day int, sec int) {
+	usedDay, usedSec := l.Used()
+	return remaining(l.dayLimit, usedDay), remaining(l.secLimit, usedSec)
+}
+
+// remaining returns the executions left under limit, never less than zero
+func remaining(limit int32, used int) int {
+	if left := int(limit) - used; left > 0 {
+		return left
+	}
+	return 0
+}
+
+// tokenBucket is a token bucket holding a single token which is refilled every interval.
+//
+// Each reservation takes the next token, so executions are spaced evenly
+// instead of bursting at the start of a fixed window.
+type tokenBucket struct {
+	mu       sync.Mutex
+	clock    Clock
+	interval time.Duration
+	next     time.Time
+}
+
+func newTokenBucket(clock Clock, interval time.Duration) *tokenBucket {
+	return &tokenBucket{
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// reserve takes the next token and returns how long to wait until it is available
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+	wait := b.next.Sub(now)
+	b.next = b.next.Add(b.interval)
 
-	return res, err
+	return wait
 }
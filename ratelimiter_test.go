@@ -1,12 +1,77 @@
 package av
 
 import (
+	"context"
+	"io/ioutil"
 	"net/http"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
+// fakeClock is a Clock which only moves when advanced.
+// After advances the clock by the duration, so waiting calls return immediately.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Advance(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward, firing the tickers which are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		for !t.next.After(c.now) {
+			// like time.Ticker, ticks are dropped for slow receivers
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+		}
+	}
+}
+
+type fakeTicker struct {
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+func (t *fakeTicker) Stop()               {}
+
 func TestRateLimiter_Do(t *testing.T) {
 	tests := []struct {
 		desc   string
@@ -38,31 +103,339 @@ func TestRateLimiter_Do(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			rl := NewRateLimiter(tt.perDay, tt.perSec)
-			ticker := time.NewTicker(time.Second)
-			endTicker := time.NewTicker(time.Duration(tt.calls/tt.perSec) * time.Second)
-			go func() {
-				for {
-					select {
-					case <-ticker.C:
-						if count := rl.secCount; count > int32(tt.perSec) {
-							t.Fatalf("too many calls: %+v", count)
-						}
-					case <-endTicker.C:
-						ticker.Stop()
-						endTicker.Stop()
-					}
-				}
-			}()
+			clock := newFakeClock()
+			rl := NewRateLimiter(tt.perDay, tt.perSec, WithClock(clock))
+			defer rl.Close()
 
+			var times []time.Time
 			for i := 0; i < tt.calls; i++ {
-				if err, _ := rl.Do(func() (*http.Response, error) { return nil, nil }); err != nil {
+				_, err := rl.Do(func() (*http.Response, error) {
+					times = append(times, clock.Now())
+					return nil, nil
+				})
+				if err != nil {
 					if !reflect.DeepEqual(err, tt.err) {
 						t.Errorf("unexpected error: %+v", err)
-						return
 					}
+					return
+				}
+			}
+			if tt.err != nil {
+				t.Errorf("expected error: %+v", tt.err)
+			}
+
+			for i := range times {
+				count := 1
+				for j := i + 1; j < len(times) && times[j].Sub(times[i]) < time.Second; j++ {
+					count++
+				}
+				if count > tt.perSec {
+					t.Fatalf("too many calls within one second: %d", count)
 				}
 			}
 		})
 	}
 }
+
+func TestRateLimiter_Do_perMinute(t *testing.T) {
+	rl := NewRateLimiterPerMinute(0, 2, 0)
+	call := func() (*http.Response, error) { return nil, nil }
+
+	for i := 0; i < 2; i++ {
+		if _, err := rl.Do(call); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = rl.Do(call)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("call was not delayed by the per-minute limit")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Simulate the minute window resetting.
+	atomic.StoreInt32(&rl.minCount, 0)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("call was not executed after the per-minute count reset")
+	}
+}
+
+// recordingClock is the real clock, recording the durations waited for
+type recordingClock struct {
+	realClock
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (c *recordingClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.waits = append(c.waits, d)
+	c.mu.Unlock()
+	return c.realClock.After(d)
+}
+
+func TestRateLimiter_Do_jitter(t *testing.T) {
+	tests := []struct {
+		desc      string
+		opts      []RateLimiterOption
+		minSpread time.Duration
+		maxSpread time.Duration
+	}{
+		{desc: "default", maxSpread: 0},
+		{desc: "jitter", opts: []RateLimiterOption{WithSleepStep(5 * time.Millisecond), WithJitter(20 * time.Millisecond)}, minSpread: 5 * time.Millisecond, maxSpread: 20 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			const waiters = 5
+			clock := &recordingClock{}
+			rl := NewRateLimiterPerMinute(0, waiters, 0, append([]RateLimiterOption{WithClock(clock)}, tt.opts...)...)
+			defer rl.Close()
+			call := func() (*http.Response, error) { return nil, nil }
+
+			for i := 0; i < waiters; i++ {
+				if _, err := rl.Do(call); err != nil {
+					t.Fatalf("unexpected error: %+v", err)
+				}
+			}
+
+			wg := &sync.WaitGroup{}
+			for i := 0; i < waiters; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, _ = rl.Do(call)
+				}()
+			}
+			time.Sleep(200 * time.Millisecond)
+
+			// Simulate the minute window resetting.
+			atomic.StoreInt32(&rl.minCount, 0)
+			wg.Wait()
+
+			clock.mu.Lock()
+			defer clock.mu.Unlock()
+			min, max := clock.waits[0], clock.waits[0]
+			for _, d := range clock.waits {
+				if d < min {
+					min = d
+				}
+				if d > max {
+					max = d
+				}
+			}
+			if spread := max - min; spread < tt.minSpread || spread > tt.maxSpread {
+				t.Errorf("unexpected spread of %d waits, want %s to %s got %s", len(clock.waits), tt.minSpread, tt.maxSpread, spread)
+			}
+			if tt.maxSpread == 0 && min != defaultSleepStep {
+				t.Errorf("unexpected sleep step, want %s got %s", defaultSleepStep, min)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_dayReset(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiter(1, 0, WithClock(clock))
+	defer rl.Close()
+	call := func() (*http.Response, error) { return nil, nil }
+
+	if _, err := rl.Do(call); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if _, err := rl.Do(call); err != ErrDailyLimitReached {
+		t.Fatalf("unexpected error, want %v got %+v", ErrDailyLimitReached, err)
+	}
+
+	reset := rl.dayResetC()
+	clock.Advance(24 * time.Hour)
+	select {
+	case <-reset:
+	case <-time.After(time.Second):
+		t.Fatal("daily count was not reset")
+	}
+
+	if _, err := rl.Do(call); err != nil {
+		t.Errorf("unexpected error after the daily reset: %+v", err)
+	}
+}
+
+func TestRateLimiter_dayReset_midnight(t *testing.T) {
+	// the fake clock starts at 19:00 US/Eastern
+	clock := newFakeClock()
+	rl := NewRateLimiter(1, 0, WithClock(clock))
+	defer rl.Close()
+	call := func() (*http.Response, error) { return nil, nil }
+
+	if _, err := rl.Do(call); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	reset := rl.dayResetC()
+	clock.Advance(4*time.Hour + 59*time.Minute)
+	select {
+	case <-reset:
+		t.Fatal("daily count was reset before midnight")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if _, err := rl.Do(call); err != ErrDailyLimitReached {
+		t.Fatalf("unexpected error before midnight, want %v got %+v", ErrDailyLimitReached, err)
+	}
+
+	clock.Advance(2 * time.Minute)
+	select {
+	case <-reset:
+	case <-time.After(time.Second):
+		t.Fatal("daily count was not reset at midnight")
+	}
+	if _, err := rl.Do(call); err != nil {
+		t.Errorf("unexpected error after midnight: %+v", err)
+	}
+}
+
+func TestRateLimiter_UsedRemaining(t *testing.T) {
+	rl := NewRateLimiter(500, 5)
+	for i := 0; i < 3; i++ {
+		if _, err := rl.Do(func() (*http.Response, error) { return nil, nil }); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+
+	if day, _ := rl.Used(); day != 3 {
+		t.Errorf("unexpected used day count, want 3 got %d", day)
+	}
+	if day, sec := rl.Remaining(); day != 497 || sec < 2 {
+		t.Errorf("unexpected remaining counts, got day %d sec %d", day, sec)
+	}
+
+	// Remaining never goes negative.
+	atomic.StoreInt32(&rl.dayCount, 501)
+	if day, _ := rl.Remaining(); day != 0 {
+		t.Errorf("unexpected remaining day count, want 0 got %d", day)
+	}
+}
+
+func TestRateLimiter_DoWait(t *testing.T) {
+	rl := NewRateLimiter(1, 0)
+	call := func() (*http.Response, error) { return nil, nil }
+
+	if _, err := rl.DoWait(context.Background(), call); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := rl.DoWait(ctx, call); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error, want %v got %+v", context.DeadlineExceeded, err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := rl.DoWait(context.Background(), call)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("call was not blocked by the daily limit: %+v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	rl.resetDay()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %+v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("call was not executed after the daily count reset")
+	}
+}
+
+func TestRateLimiter_DoWait_perMinute(t *testing.T) {
+	rl := NewRateLimiterPerMinute(0, 1, 0)
+	call := func() (*http.Response, error) { return nil, nil }
+
+	if _, err := rl.DoWait(context.Background(), call); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := rl.DoWait(ctx, call); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error, want %v got %+v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call was blocked by the per minute limit after the context was done, for %s", elapsed)
+	}
+	if day, _ := rl.Used(); day != 1 {
+		t.Errorf("unexpected daily count, want 1 got %d", day)
+	}
+}
+
+func TestRateLimiter_shared(t *testing.T) {
+	const (
+		perSec  = 5
+		clients = 2
+		calls   = 5
+	)
+	rl := NewRateLimiter(0, perSec)
+
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(sampleTimeSeriesData)),
+		}, nil
+	})
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < clients; i++ {
+		conn := NewConnection(WithRateLimiter(rl), WithTransport(transport))
+		client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+		for j := 0; j < calls; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST"); err != nil {
+					t.Errorf("unexpected error: %+v", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if day, _ := rl.Used(); day != clients*calls {
+		t.Errorf("unexpected shared day count, want %d got %d", clients*calls, day)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	// Allow for timer jitter when a call wakes up late.
+	const window = time.Second - 10*time.Millisecond
+	for i := range times {
+		count := 1
+		for j := i + 1; j < len(times) && times[j].Sub(times[i]) < window; j++ {
+			count++
+		}
+		if count > perSec {
+			t.Fatalf("too many calls within one second across clients: %d", count)
+		}
+	}
+}
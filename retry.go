@@ -0,0 +1,50 @@
+package av
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy decides whether a request is retried, given its response or error.
+// The response is nil when the request failed. A policy reading the response body
+// must replace it, as the body is handed to the caller when it is not retried.
+type RetryPolicy func(resp *http.Response, err error) bool
+
+// DefaultRetryPolicy retries requests failing with a connection error, and responses
+// with a 5xx status. Requests cancelled by their context, even when wrapped
+// by the http.Client, and 4xx responses are never retried.
+func DefaultRetryPolicy(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryDelay returns the backoff before the next attempt, after the given number of attempts.
+// The delay doubles with every attempt, with a random jitter of up to half the delay.
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base << uint(attempts-1)
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// discardResponse drains and closes the body of a response which is retried,
+// so the underlying connection can be reused
+func discardResponse(response *http.Response) {
+	if response == nil || response.Body == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, response.Body)
+	response.Body.Close()
+}
@@ -0,0 +1,147 @@
+package av
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	tests := []struct {
+		desc     string
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{desc: "ok", resp: &http.Response{StatusCode: http.StatusOK}},
+		{desc: "bad request", resp: &http.Response{StatusCode: http.StatusBadRequest}},
+		{desc: "too many requests", resp: &http.Response{StatusCode: http.StatusTooManyRequests}},
+		{desc: "bad gateway", resp: &http.Response{StatusCode: http.StatusBadGateway}, expected: true},
+		{desc: "service unavailable", resp: &http.Response{StatusCode: http.StatusServiceUnavailable}, expected: true},
+		{desc: "connection error", err: errors.New("connection reset"), expected: true},
+		{desc: "cancelled", err: context.Canceled},
+		{desc: "deadline exceeded", err: context.DeadlineExceeded},
+		{desc: "wrapped cancel", err: &url.Error{Op: "Get", URL: "https://www.alphavantage.co/query", Err: context.Canceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if retry := DefaultRetryPolicy(tt.resp, tt.err); retry != tt.expected {
+				t.Errorf("unexpected retry, want %t got %t", tt.expected, retry)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	called := false
+	policy := func(resp *http.Response, err error) bool {
+		called = true
+		return false
+	}
+
+	conn := NewConnection(WithRetryPolicy(policy)).(*avConnection)
+	defer conn.Close()
+	conn.copts.retryPolicy(nil, nil)
+	if !called {
+		t.Error("custom retry policy was not set")
+	}
+
+	conn = NewConnection(WithRetryPolicy(nil)).(*avConnection)
+	defer conn.Close()
+	if conn.copts.retryPolicy == nil {
+		t.Error("nil retry policy did not fall back to the default")
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	connErr := errors.New("connection reset")
+
+	tests := []struct {
+		desc     string
+		statuses []int
+		err      error
+		attempts int
+		status   int
+		fails    bool
+	}{
+		{desc: "fails twice then succeeds", statuses: []int{503, 502, 200}, attempts: 3, status: 200},
+		{desc: "always fails", statuses: []int{503, 503, 503, 503}, attempts: 3, status: 503},
+		{desc: "connection error", err: connErr, attempts: 3, fails: true},
+		{desc: "client error", statuses: []int{400, 200}, attempts: 1, status: 400},
+		{desc: "too many requests", statuses: []int{429, 200}, attempts: 1, status: 429},
+		{desc: "wrapped cancel", err: context.Canceled, attempts: 1, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var requests int
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &http.Response{
+					StatusCode: tt.statuses[requests-1],
+					Body:       ioutil.NopCloser(strings.NewReader(sampleTimeSeriesData)),
+					Request:    req,
+				}, nil
+			})
+			rl := NewRateLimiter(0, 0)
+			defer rl.Close()
+			conn := NewConnection(WithTransport(transport), WithRateLimiter(rl), WithRetry(3, time.Millisecond))
+
+			res, err := conn.Request(context.Background(), &url.URL{Path: pathQuery})
+			if tt.fails {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error, got %v", err)
+				}
+				res.Body.Close()
+				if res.StatusCode != tt.status {
+					t.Errorf("unexpected status, want %d got %d", tt.status, res.StatusCode)
+				}
+			}
+			if requests != tt.attempts {
+				t.Errorf("unexpected attempts, want %d got %d", tt.attempts, requests)
+			}
+			// every attempt passes through the rate limiter
+			if day, _ := rl.Used(); day != tt.attempts {
+				t.Errorf("unexpected rate limiter count, want %d got %d", tt.attempts, day)
+			}
+		})
+	}
+}
+
+func TestWithRetry_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var requests int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	})
+	conn := NewConnection(WithTransport(transport), WithRetry(3, time.Hour))
+	defer conn.(io.Closer).Close()
+
+	if _, err := conn.Request(ctx, &url.URL{Path: pathQuery}); err != context.Canceled {
+		t.Errorf("unexpected error, want %v got %v", context.Canceled, err)
+	}
+	if requests != 1 {
+		t.Errorf("unexpected attempts, want 1 got %d", requests)
+	}
+}
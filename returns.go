@@ -0,0 +1,50 @@
+package av
+
+import (
+	"math"
+	"time"
+)
+
+// ReturnValue is the return of a symbol at a given time since the previous value
+type ReturnValue struct {
+	Time  time.Time
+	Value float64
+}
+
+// PercentChange computes the percent change of the close price between consecutive values,
+// i.e. 1.5 for a 1.5% increase. Values are expected from past to present.
+// The first value has no previous close and is omitted, as are values following a zero close.
+func PercentChange(values []*TimeSeriesValue) []*ReturnValue {
+	return returns(values, func(prev, cur float64) float64 {
+		return (cur - prev) / prev * 100
+	})
+}
+
+// LogReturns computes the logarithmic return of the close price between consecutive values.
+// Values are expected from past to present.
+// The first value has no previous close and is omitted, as are values following a zero close.
+func LogReturns(values []*TimeSeriesValue) []*ReturnValue {
+	return returns(values, func(prev, cur float64) float64 {
+		return math.Log(cur / prev)
+	})
+}
+
+// returns computes the return between the close prices of consecutive values
+func returns(values []*TimeSeriesValue, f func(prev, cur float64) float64) []*ReturnValue {
+	if len(values) < 2 {
+		return nil
+	}
+
+	result := make([]*ReturnValue, 0, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		prev := values[i-1].Close
+		if prev == 0 {
+			continue
+		}
+		result = append(result, &ReturnValue{
+			Time:  values[i].Time,
+			Value: f(prev, values[i].Close),
+		})
+	}
+	return result
+}
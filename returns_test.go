@@ -0,0 +1,65 @@
+package av
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPercentChange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	values := []*TimeSeriesValue{
+		{Time: day(4), Close: 100},
+		{Time: day(5), Close: 110},
+		{Time: day(6), Close: 99},
+		{Time: day(7), Close: 0},
+		{Time: day(8), Close: 50},
+	}
+
+	tests := []struct {
+		desc     string
+		f        func([]*TimeSeriesValue) []*ReturnValue
+		expected []*ReturnValue
+	}{
+		{
+			desc: "percent change",
+			f:    PercentChange,
+			expected: []*ReturnValue{
+				{Time: day(5), Value: 10},
+				{Time: day(6), Value: -10},
+				{Time: day(7), Value: -100},
+			},
+		},
+		{
+			desc: "log returns",
+			f:    LogReturns,
+			expected: []*ReturnValue{
+				{Time: day(5), Value: math.Log(1.1)},
+				{Time: day(6), Value: math.Log(0.9)},
+				{Time: day(7), Value: math.Inf(-1)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			result := tt.f(values)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("unexpected result count, want %d got %d", len(tt.expected), len(result))
+			}
+			for i, r := range result {
+				want := tt.expected[i]
+				if !r.Time.Equal(want.Time) {
+					t.Errorf("unexpected time at %d, want %s got %s", i, want.Time, r.Time)
+				}
+				if r.Value != want.Value && math.Abs(r.Value-want.Value) > 1e-9 {
+					t.Errorf("unexpected value at %s, want %f got %f", r.Time, want.Value, r.Value)
+				}
+			}
+		})
+	}
+
+	if result := PercentChange(values[:1]); result != nil {
+		t.Errorf("unexpected result for a single value, got %v", result)
+	}
+}
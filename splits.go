@@ -0,0 +1,85 @@
+package av
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	valueSplitsEndpoint = "SPLITS"
+
+	// splitDateFormat is the format of the effective date of a split
+	splitDateFormat = "2006-01-02"
+)
+
+// SplitEvent is a split of a symbol, i.e. a SplitFactor of 2 for a 2-for-1 split
+type SplitEvent struct {
+	EffectiveDate time.Time
+	SplitFactor   float64
+}
+
+// sortSplitEventsByDate allows SplitEvent
+// slices to be sorted by date in ascending order
+type sortSplitEventsByDate []*SplitEvent
+
+func (b sortSplitEventsByDate) Len() int { return len(b) }
+func (b sortSplitEventsByDate) Less(i, j int) bool {
+	return b[i].EffectiveDate.Before(b[j].EffectiveDate)
+}
+func (b sortSplitEventsByDate) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+
+// splitData is the json body of a splits response
+type splitData struct {
+	Symbol string `json:"symbol"`
+	Data   []struct {
+		EffectiveDate string `json:"effective_date"`
+		SplitFactor   string `json:"split_factor"`
+	} `json:"data"`
+}
+
+// parseSplitData will parse json data from a reader
+func parseSplitData(r io.Reader) ([]*SplitEvent, error) {
+	var data splitData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding splits")
+	}
+
+	events := make([]*SplitEvent, 0, len(data.Data))
+	for _, d := range data.Data {
+		t, err := parseDate(d.EffectiveDate, splitDateFormat)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing effective date %s", d.EffectiveDate)
+		}
+		f, err := parseFloat(d.SplitFactor)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing split factor %s", d.SplitFactor)
+		}
+		events = append(events, &SplitEvent{EffectiveDate: t, SplitFactor: f})
+	}
+
+	// sort events by date
+	sort.Sort(sortSplitEventsByDate(events))
+
+	return events, nil
+}
+
+// Splits queries the historical splits of a symbol.
+// Data is returned from past to present.
+func (c *Client) Splits(ctx context.Context, symbol string) ([]*SplitEvent, error) {
+	endpoint := c.buildRequestPath(map[string]string{
+		queryEndpoint: valueSplitsEndpoint,
+		querySymbol:   symbol,
+		queryDataType: valueDataTypeJson,
+	})
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseSplitData(body)
+}
@@ -0,0 +1,37 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_Splits(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=json&function=SPLITS&outputsize=compact&symbol=IBM"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleSplitData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	events, err := client.Splits(context.Background(), "IBM")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+
+	expectedEvents := []*SplitEvent{
+		{EffectiveDate: time.Date(1999, 5, 27, 0, 0, 0, 0, time.UTC), SplitFactor: 2},
+		{EffectiveDate: time.Date(2021, 11, 4, 0, 0, 0, 0, time.UTC), SplitFactor: 1.046},
+	}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("unexpected splits, want %v got %v", expectedEvents, events)
+	}
+}
@@ -0,0 +1,424 @@
+package av
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	queryTimePeriod  = "time_period"
+	querySeriesType  = "series_type"
+	queryFastKPeriod = "fastkperiod"
+	querySlowKPeriod = "slowkperiod"
+	querySlowDPeriod = "slowdperiod"
+	querySlowKMAType = "slowkmatype"
+	querySlowDMAType = "slowdmatype"
+
+	valueSTOCHEndpoint = "STOCH"
+
+	columnSlowK = "SlowK"
+	columnSlowD = "SlowD"
+)
+
+// ErrInvalidIndicatorParam is returned when a technical indicator parameter is malformed
+var ErrInvalidIndicatorParam = errors.New("invalid technical indicator parameter")
+
+var (
+	// indicatorDateFormats are the expected date formats in technical indicator data
+	indicatorDateFormats = []string{
+		"2006-01-02",
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+	}
+)
+
+// SeriesType specifies the price used to calculate a technical indicator.
+// For valid options, see the SeriesType* package constants.
+// The zero value is SeriesTypeClose.
+type SeriesType uint8
+
+const (
+	SeriesTypeClose SeriesType = iota
+	SeriesTypeOpen
+	SeriesTypeHigh
+	SeriesTypeLow
+)
+
+func (t SeriesType) String() string {
+	switch t {
+	case SeriesTypeClose:
+		return "SeriesTypeClose"
+	case SeriesTypeOpen:
+		return "SeriesTypeOpen"
+	case SeriesTypeHigh:
+		return "SeriesTypeHigh"
+	case SeriesTypeLow:
+		return "SeriesTypeLow"
+	}
+	return "SeriesTypeUnknown"
+}
+
+// IsValid reports whether the SeriesType is one of the SeriesType* package constants
+func (t SeriesType) IsValid() bool {
+	return t <= SeriesTypeLow
+}
+
+// MarshalJSON encodes the SeriesType as its Alpha Vantage name, i.e. "close".
+// ErrInvalidIndicatorParam is returned for an invalid SeriesType.
+func (t SeriesType) MarshalJSON() ([]byte, error) {
+	if !t.IsValid() {
+		return nil, errors.Wrapf(ErrInvalidIndicatorParam, "invalid %s %d", querySeriesType, uint8(t))
+	}
+	return json.Marshal(t.keyName())
+}
+
+// keyName returns the name of the SeriesType used for Alpha Vantage API
+func (t SeriesType) keyName() string {
+	switch t {
+	case SeriesTypeClose:
+		return "close"
+	case SeriesTypeOpen:
+		return "open"
+	case SeriesTypeHigh:
+		return "high"
+	case SeriesTypeLow:
+		return "low"
+	}
+	return "unknown"
+}
+
+// ParseSeriesType returns the SeriesType of its Alpha Vantage name, i.e. "close".
+// ErrInvalidIndicatorParam is returned for unknown names.
+func ParseSeriesType(s string) (SeriesType, error) {
+	for t := SeriesTypeClose; t <= SeriesTypeLow; t++ {
+		if t.keyName() == s {
+			return t, nil
+		}
+	}
+	return 0, errors.Wrapf(ErrInvalidIndicatorParam, "unknown %s %q", querySeriesType, s)
+}
+
+// validateIndicatorParams checks the typed parameters of a technical indicator, if present
+func validateIndicatorParams(params map[string]string) error {
+	if value, ok := params[queryTimePeriod]; ok {
+		if period, err := strconv.Atoi(value); err != nil || period <= 0 {
+			return errors.Wrapf(ErrInvalidIndicatorParam, "%s %q is not a positive integer", queryTimePeriod, value)
+		}
+	}
+	if value, ok := params[querySeriesType]; ok {
+		if _, err := ParseSeriesType(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndicatorValue is a piece of data for a given time about a technical indicator.
+// Values are keyed by the column names returned by Alpha Vantage (i.e. "SlowK").
+type IndicatorValue struct {
+	Time   time.Time
+	Values map[string]float64
+
+	// columns are the value column names in the order returned by Alpha Vantage
+	columns []string
+}
+
+// Value returns the value of the first column.
+// It is a shortcut for indicators that return a single value (i.e. ADX).
+func (v *IndicatorValue) Value() float64 {
+	if len(v.columns) == 0 {
+		return 0
+	}
+	return v.Values[v.columns[0]]
+}
+
+// Column returns the value of the named column, i.e. ColumnSine.
+// The boolean is false if the indicator has no such column.
+func (v *IndicatorValue) Column(name string) (float64, bool) {
+	f, ok := v.Values[name]
+	return f, ok
+}
+
+// Columns returns the value column names in the order returned by Alpha Vantage
+func (v *IndicatorValue) Columns() []string {
+	// the columns are shared between the values of a response, so they are copied
+	columns := make([]string, len(v.columns))
+	copy(columns, v.columns)
+	return columns
+}
+
+// lookup returns the values of the given columns in order.
+// An error is returned if any of the columns are missing.
+func (v *IndicatorValue) lookup(columns ...string) ([]float64, error) {
+	values := make([]float64, len(columns))
+	for i, column := range columns {
+		f, ok := v.Values[column]
+		if !ok {
+			return nil, errors.Errorf("missing column %s", column)
+		}
+		values[i] = f
+	}
+	return values, nil
+}
+
+// sortIndicatorValuesByDate allows IndicatorValue
+// slices to be sorted by date in ascending order
+type sortIndicatorValuesByDate []*IndicatorValue
+
+func (b sortIndicatorValuesByDate) Len() int           { return len(b) }
+func (b sortIndicatorValuesByDate) Less(i, j int) bool { return b[i].Time.Before(b[j].Time) }
+func (b sortIndicatorValuesByDate) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// parseIndicatorData will parse csv data from a reader.
+// The first column is the timestamp, every other column is keyed by its header name.
+func parseIndicatorData(r io.Reader) ([]*IndicatorValue, error) {
+
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true // optimization
+	reader.LazyQuotes = true
+	reader.TrailingComma = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// the header is reused by the reader, so it must be copied
+	columns := make([]string, len(header))
+	copy(columns, header)
+
+	values := make([]*IndicatorValue, 0, 64)
+
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		value, err := parseIndicatorRecord(columns, record)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	// sort values by date
+	sort.Sort(sortIndicatorValuesByDate(values))
+
+	return values, nil
+
+}
+
+// parseIndicatorRecord will parse an individual csv record
+func parseIndicatorRecord(columns []string, s []string) (*IndicatorValue, error) {
+	// these are the expected columns in the csv record
+	const (
+		timestamp = iota
+		firstValue
+	)
+
+	value := &IndicatorValue{
+		Values:  make(map[string]float64, len(columns)-firstValue),
+		columns: columns[firstValue:],
+	}
+
+	d, err := parseDate(s[timestamp], indicatorDateFormats...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing timestamp %s", s[timestamp])
+	}
+	value.Time = d
+
+	for i := firstValue; i < len(columns) && i < len(s); i++ {
+		f, err := parseFloat(s[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing %s %s", columns[i], s[i])
+		}
+		value.Values[columns[i]] = f
+	}
+
+	return value, nil
+}
+
+// TechnicalIndicator queries any technical indicator for a symbol, i.e. "MACD" or "BBANDS".
+// Additional parameters specific to the indicator (i.e. "time_period" or "series_type")
+// are added to the query. Each value holds the indicator columns keyed by name.
+// ErrInvalidIndicatorParam is returned if "time_period" or "series_type" are malformed.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicator(ctx context.Context, indicator string, symbol string, interval TimeInterval, params map[string]string) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, indicator, symbol, interval, params)
+}
+
+// technicalIndicator queries a technical indicator for a symbol at the given interval.
+// Additional parameters specific to the indicator are added to the query.
+// Data is returned from past to present.
+func (c *Client) technicalIndicator(ctx context.Context, function string, symbol string, interval TimeInterval, params map[string]string) ([]*IndicatorValue, error) {
+	if !interval.IsValid() {
+		return nil, ErrInvalidInterval
+	}
+	if err := validateIndicatorParams(params); err != nil {
+		return nil, err
+	}
+	query := make(map[string]string, len(params)+3)
+	for key, value := range params {
+		query[key] = value
+	}
+	// the indicator parameters must not override the base parameters
+	query[queryEndpoint] = function
+	query[querySymbol] = symbol
+	query[queryInterval] = interval.keyName()
+	endpoint := c.buildRequestPath(query)
+	body, err := c.request(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseIndicatorData(body)
+}
+
+// seriesIndicator queries an indicator calculated over a time period of a price series, i.e. SMA
+func (c *Client) seriesIndicator(ctx context.Context, function string, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, function, symbol, interval, map[string]string{
+		queryTimePeriod: strconv.Itoa(timePeriod),
+		querySeriesType: seriesType.keyName(),
+	})
+}
+
+// periodIndicator queries an indicator calculated over a time period of the high, low and close prices, i.e. ADX
+func (c *Client) periodIndicator(ctx context.Context, function string, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, function, symbol, interval, map[string]string{
+		queryTimePeriod: strconv.Itoa(timePeriod),
+	})
+}
+
+// TechnicalIndicatorADX queries the average directional movement index of a symbol.
+// It is equivalent to ADX.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorADX(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.ADX(ctx, symbol, interval, timePeriod)
+}
+
+// TechnicalIndicatorATR queries the average true range of a symbol.
+// It is equivalent to ATR.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorATR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.ATR(ctx, symbol, interval, timePeriod)
+}
+
+// TechnicalIndicatorWILLR queries the Williams' %R of a symbol.
+// It is equivalent to WILLR.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorWILLR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.WILLR(ctx, symbol, interval, timePeriod)
+}
+
+// TechnicalIndicatorCCI queries the commodity channel index of a symbol.
+// It is equivalent to CCI.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorCCI(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.CCI(ctx, symbol, interval, timePeriod)
+}
+
+// TechnicalIndicatorAROON queries the aroon up and aroon down lines of a symbol.
+// It is equivalent to AROON.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorAROON(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*AROONValue, error) {
+	return c.AROON(ctx, symbol, interval, timePeriod)
+}
+
+// TechnicalIndicatorMFI queries the money flow index of a symbol.
+// It is equivalent to MFI.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorMFI(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.MFI(ctx, symbol, interval, timePeriod)
+}
+
+// TechnicalIndicatorOBV queries the on balance volume of a symbol.
+// It is equivalent to OBV.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorOBV(ctx context.Context, symbol string, interval TimeInterval) ([]*IndicatorValue, error) {
+	return c.OBV(ctx, symbol, interval)
+}
+
+// STOCHParams are the parameters of the stochastic oscillator.
+// Zero values are replaced by the Alpha Vantage defaults.
+type STOCHParams struct {
+	// FastKPeriod is the time period of the fastk moving average, 5 by default
+	FastKPeriod int
+	// SlowKPeriod is the time period of the slowk moving average, 3 by default
+	SlowKPeriod int
+	// SlowDPeriod is the time period of the slowd moving average, 3 by default
+	SlowDPeriod int
+	// SlowKMAType is the moving average type of the slowk moving average, MATypeSMA by default
+	SlowKMAType MAType
+	// SlowDMAType is the moving average type of the slowd moving average, MATypeSMA by default
+	SlowDMAType MAType
+}
+
+// withDefaults returns a copy of the params with zero values replaced by defaults
+func (p STOCHParams) withDefaults() STOCHParams {
+	if p.FastKPeriod == 0 {
+		p.FastKPeriod = 5
+	}
+	if p.SlowKPeriod == 0 {
+		p.SlowKPeriod = 3
+	}
+	if p.SlowDPeriod == 0 {
+		p.SlowDPeriod = 3
+	}
+	return p
+}
+
+// STOCHValue is a piece of data for a given time about the stochastic oscillator
+type STOCHValue struct {
+	Time  time.Time
+	SlowK float64
+	SlowD float64
+}
+
+// TechnicalIndicatorSTOCH queries the stochastic oscillator of a symbol.
+// Unlike STOCH, every parameter is sent, with zero values replaced by defaults.
+// Data is returned from past to present.
+func (c *Client) TechnicalIndicatorSTOCH(ctx context.Context, symbol string, interval TimeInterval, params STOCHParams) ([]*STOCHValue, error) {
+	params = params.withDefaults()
+	return c.stoch(ctx, symbol, interval, map[string]string{
+		queryFastKPeriod: strconv.Itoa(params.FastKPeriod),
+		querySlowKPeriod: strconv.Itoa(params.SlowKPeriod),
+		querySlowDPeriod: strconv.Itoa(params.SlowDPeriod),
+		querySlowKMAType: strconv.Itoa(int(params.SlowKMAType)),
+		querySlowDMAType: strconv.Itoa(int(params.SlowDMAType)),
+	})
+}
+
+// stoch queries the stochastic oscillator and maps its columns to STOCHValue
+func (c *Client) stoch(ctx context.Context, symbol string, interval TimeInterval, params map[string]string) ([]*STOCHValue, error) {
+	values, err := c.technicalIndicator(ctx, valueSTOCHEndpoint, symbol, interval, params)
+	if err != nil {
+		return nil, err
+	}
+
+	stoch := make([]*STOCHValue, 0, len(values))
+	for _, value := range values {
+		v, err := value.lookup(columnSlowK, columnSlowD)
+		if err != nil {
+			return nil, err
+		}
+		stoch = append(stoch, &STOCHValue{
+			Time:  value.Time,
+			SlowK: v[0],
+			SlowD: v[1],
+		})
+	}
+	return stoch, nil
+}
@@ -0,0 +1,391 @@
+package av
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestClient_TechnicalIndicatorSTOCH_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc     string
+		params   STOCHParams
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			expected: "query?apikey=test&datatype=csv&fastkperiod=5&function=STOCH&interval=15min&outputsize=compact&slowdmatype=0&slowdperiod=3&slowkmatype=0&slowkperiod=3&symbol=TEST",
+		},
+		{
+			desc:     "overrides",
+			params:   STOCHParams{FastKPeriod: 14, SlowKPeriod: 5, SlowDPeriod: 5, SlowKMAType: MATypeEMA, SlowDMAType: MATypeEMA},
+			expected: "query?apikey=test&datatype=csv&fastkperiod=14&function=STOCH&interval=15min&outputsize=compact&slowdmatype=1&slowdperiod=5&slowkmatype=1&slowkperiod=5&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleSTOCHData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.TechnicalIndicatorSTOCH(context.Background(), "TEST", TimeIntervalFifteenMinute, tt.params)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_TechnicalIndicatorSTOCH_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleSTOCHData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorSTOCH(context.Background(), "TEST", TimeIntervalFifteenMinute, STOCHParams{})
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 4 {
+		t.Fatalf("unexpected result count, want 4 got %d", len(result))
+	}
+	last := result[len(result)-1]
+	if last.SlowK != 38.1852 || last.SlowD != 52.4075 {
+		t.Errorf("unexpected last value, got %+v", last)
+	}
+}
+
+func TestParseIndicatorData_byHeader(t *testing.T) {
+	const data = `time,SlowD,SlowK
+2024-03-08,52.4075,38.1852`
+
+	values, err := parseIndicatorData(NewBuffCloser(data))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	v, err := values[0].lookup(columnSlowK, columnSlowD)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if v[0] != 38.1852 || v[1] != 52.4075 {
+		t.Errorf("columns were not mapped by header, got %v", v)
+	}
+	if _, err := values[0].lookup("SlowJ"); err == nil {
+		t.Error("expected error for missing column")
+	}
+}
+
+func TestClient_TechnicalIndicatorADX(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=ADX&interval=60min&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleADXData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorADX(context.Background(), "TEST", TimeIntervalSixtyMinute, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != 23.5108 {
+		t.Errorf("unexpected value, want 23.5108 got %f", v)
+	}
+}
+
+func TestClient_TechnicalIndicatorATR(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=ATR&interval=60min&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleATRData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorATR(context.Background(), "TEST", TimeIntervalSixtyMinute, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if v := result[0].Value(); v != 0.4930 {
+		t.Errorf("unexpected value, want 0.4930 got %f", v)
+	}
+}
+
+func TestClient_TechnicalIndicator(t *testing.T) {
+	tests := []struct {
+		desc      string
+		indicator string
+		params    map[string]string
+		data      string
+		expected  string
+		columns   map[string]float64
+	}{
+		{
+			desc:      "single column",
+			indicator: "SMA",
+			params:    map[string]string{"time_period": "10", "series_type": "open"},
+			data:      sampleSMAData,
+			expected:  "query?apikey=test&datatype=csv&function=SMA&interval=5min&outputsize=compact&series_type=open&symbol=TEST&time_period=10",
+			columns:   map[string]float64{"SMA": 407.8810},
+		},
+		{
+			desc:      "multiple columns",
+			indicator: "MACD",
+			params:    map[string]string{"series_type": "close", "function": "IGNORED"},
+			data:      sampleMACDData,
+			expected:  "query?apikey=test&datatype=csv&function=MACD&interval=5min&outputsize=compact&series_type=close&symbol=TEST",
+			columns:   map[string]float64{"MACD": 5.2311, "MACD_Hist": -0.4120, "MACD_Signal": 5.6431},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(tt.data),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := client.TechnicalIndicator(context.Background(), tt.indicator, "TEST", TimeIntervalFiveMinute, tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			last := result[len(result)-1]
+			if !reflect.DeepEqual(last.Values, tt.columns) {
+				t.Errorf("unexpected values, want %v got %v", tt.columns, last.Values)
+			}
+		})
+	}
+}
+
+func TestClient_TechnicalIndicator_invalidParams(t *testing.T) {
+	tests := []struct {
+		desc   string
+		params map[string]string
+	}{
+		{desc: "time period not a number", params: map[string]string{"time_period": "abc"}},
+		{desc: "time period not positive", params: map[string]string{"time_period": "0"}},
+		{desc: "unknown series type", params: map[string]string{"series_type": "median"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			conn := NewResponseConnection(&http.Response{})
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, err := client.TechnicalIndicator(context.Background(), "SMA", "TEST", TimeIntervalDaily, tt.params)
+			if errors.Cause(err) != ErrInvalidIndicatorParam {
+				t.Errorf("unexpected error, want %v got %v", ErrInvalidIndicatorParam, err)
+			}
+			if conn.endpoint != nil {
+				t.Errorf("request was made to %s", conn.endpoint)
+			}
+		})
+	}
+
+	// the typed wrappers are validated the same way
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(NewResponseConnection(&http.Response{})))
+	if _, err := client.RSI(context.Background(), "TEST", TimeIntervalDaily, -1, SeriesTypeClose); errors.Cause(err) != ErrInvalidIndicatorParam {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidIndicatorParam, err)
+	}
+	if _, err := client.RSI(context.Background(), "TEST", TimeIntervalDaily, 14, SeriesTypeLow+1); errors.Cause(err) != ErrInvalidIndicatorParam {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidIndicatorParam, err)
+	}
+}
+
+func TestParseSeriesType(t *testing.T) {
+	for _, seriesType := range []SeriesType{SeriesTypeClose, SeriesTypeOpen, SeriesTypeHigh, SeriesTypeLow} {
+		parsed, err := ParseSeriesType(seriesType.keyName())
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		if parsed != seriesType {
+			t.Errorf("unexpected series type, want %s got %s", seriesType, parsed)
+		}
+	}
+	if _, err := ParseSeriesType("Close"); errors.Cause(err) != ErrInvalidIndicatorParam {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidIndicatorParam, err)
+	}
+}
+
+func TestSeriesType_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		SeriesType SeriesType
+	}{})
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	// the zero value is close
+	if expected := `{"SeriesType":"close"}`; string(data) != expected {
+		t.Errorf("unexpected json, want %s got %s", expected, data)
+	}
+
+	invalid := SeriesType(9)
+	if invalid.IsValid() {
+		t.Error("invalid series type is valid")
+	}
+	if _, err := invalid.MarshalJSON(); errors.Cause(err) != ErrInvalidIndicatorParam {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidIndicatorParam, err)
+	}
+}
+
+func TestClient_TechnicalIndicatorOBV(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=OBV&interval=5min&outputsize=compact&symbol=TEST"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleOBVData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorOBV(context.Background(), "TEST", TimeIntervalFiveMinute)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	for i := 1; i < len(result); i++ {
+		if !result[i-1].Time.Before(result[i].Time) {
+			t.Fatalf("results are not sorted from past to present")
+		}
+	}
+	if v := result[len(result)-1].Value(); v != 31946730 {
+		t.Errorf("unexpected value, want 31946730 got %f", v)
+	}
+}
+
+func TestClient_TechnicalIndicatorWILLR(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=WILLR&interval=60min&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleWILLRData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorWILLR(context.Background(), "TEST", TimeIntervalSixtyMinute, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 5 {
+		t.Fatalf("unexpected result count, want 5 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != -100 {
+		t.Errorf("unexpected value, want -100 got %f", v)
+	}
+}
+
+func TestClient_TechnicalIndicatorCCI(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=CCI&interval=60min&outputsize=compact&symbol=TEST&time_period=14"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleCCIData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorCCI(context.Background(), "TEST", TimeIntervalSixtyMinute, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 5 {
+		t.Fatalf("unexpected result count, want 5 got %d", len(result))
+	}
+	if v := result[len(result)-1].Value(); v != -184.3312 {
+		t.Errorf("unexpected value, want -184.3312 got %f", v)
+	}
+}
+
+func TestClient_TechnicalIndicatorAROON(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=AROON&interval=60min&outputsize=compact&symbol=TEST&time_period=25"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleAROONData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorAROON(context.Background(), "TEST", TimeIntervalSixtyMinute, 25)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	first := result[0]
+	if first.AroonUp != 85.7143 || first.AroonDown != 21.4286 {
+		t.Errorf("unexpected value, want up 85.7143 down 21.4286 got up %f down %f", first.AroonUp, first.AroonDown)
+	}
+}
+
+func TestClient_TechnicalIndicatorMFI(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleMFIData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.TechnicalIndicatorMFI(context.Background(), "TEST", TimeIntervalDaily, 14)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	if v := result[0].Value(); v != 48.0127 {
+		t.Errorf("unexpected value, want 48.0127 got %f", v)
+	}
+}
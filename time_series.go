@@ -4,11 +4,36 @@ import (
 	"encoding/csv"
 	"io"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrInvalidSeries is returned when a TimeSeries is not one of the TimeSeries* package constants
+	ErrInvalidSeries = errors.New("invalid time series")
+	// ErrInvalidInterval is returned when a TimeInterval is not one of the TimeInterval* package constants,
+	// or not an intraday interval when one is required
+	ErrInvalidInterval = errors.New("invalid time interval")
+	// ErrInvalidMonth is returned when a month is not a calendar month or is in the future
+	ErrInvalidMonth = errors.New("invalid month")
+	// ErrIntradayOnly is returned when a RequestOption for intraday time series is given to another request
+	ErrIntradayOnly = errors.New("option only applies to intraday time series")
+	// ErrNoData is returned when Alpha Vantage responds to a time series request without values,
+	// i.e. for some unknown symbols
+	ErrNoData = errors.New("no time series data")
+	// ErrTimeSeriesOnly is returned when a RequestOption for time series is given to another request
+	ErrTimeSeriesOnly = errors.New("option only applies to time series")
+	// ErrStreamJSON is returned when a time series stream is queried as json, streams only read csv
+	ErrStreamJSON = errors.New("time series streams can not be queried as json")
+	// ErrInvalidValue is the cause of errors returned by TimeSeriesValue.Validate
+	ErrInvalidValue = errors.New("invalid time series value")
+)
+
+// timeSeriesPrefix is the prefix of the functions of all time series, i.e. TIME_SERIES_DAILY
+const timeSeriesPrefix = "TIME_SERIES_"
+
 // TimeSeries specifies a given time series to query for.
 // For valid options, see the TimeSeries* package constants.
 type TimeSeries uint8
@@ -43,6 +68,11 @@ func (t TimeSeries) String() string {
 	return "TimeSeriesUnknown"
 }
 
+// IsValid reports whether the TimeSeries is one of the TimeSeries* package constants
+func (t TimeSeries) IsValid() bool {
+	return t <= TimeSeriesMonthlyAdjusted
+}
+
 // keyName returns the name of the TimeSeries used for Alpha Vantage API
 func (t TimeSeries) keyName() string {
 	switch t {
@@ -66,6 +96,9 @@ func (t TimeSeries) keyName() string {
 
 // TimeInterval specifies a frequency to query for intraday stock data.
 // For valid options, see the TimeInterval* package constants.
+//
+// TimeIntervalDaily, TimeIntervalWeekly and TimeIntervalMonthly are not intraday
+// frequencies and are only accepted by technical indicators.
 type TimeInterval uint8
 
 const (
@@ -74,6 +107,9 @@ const (
 	TimeIntervalFifteenMinute
 	TimeIntervalThirtyMinute
 	TimeIntervalSixtyMinute
+	TimeIntervalDaily
+	TimeIntervalWeekly
+	TimeIntervalMonthly
 )
 
 func (t TimeInterval) String() string {
@@ -88,10 +124,26 @@ func (t TimeInterval) String() string {
 		return "TimeIntervalThirtyMinute"
 	case TimeIntervalSixtyMinute:
 		return "TimeIntervalSixtyMinute"
+	case TimeIntervalDaily:
+		return "TimeIntervalDaily"
+	case TimeIntervalWeekly:
+		return "TimeIntervalWeekly"
+	case TimeIntervalMonthly:
+		return "TimeIntervalMonthly"
 	}
 	return "TimeIntervalUnknown"
 }
 
+// IsValid reports whether the TimeInterval is one of the TimeInterval* package constants
+func (t TimeInterval) IsValid() bool {
+	return t <= TimeIntervalMonthly
+}
+
+// isIntraday reports whether the TimeInterval is an intraday frequency
+func (t TimeInterval) isIntraday() bool {
+	return t <= TimeIntervalSixtyMinute
+}
+
 // keyName returns the name of the TimeInterval used for Alpha Vantage API
 func (t TimeInterval) keyName() string {
 	switch t {
@@ -105,15 +157,141 @@ func (t TimeInterval) keyName() string {
 		return "30min"
 	case TimeIntervalSixtyMinute:
 		return "60min"
+	case TimeIntervalDaily:
+		return "daily"
+	case TimeIntervalWeekly:
+		return "weekly"
+	case TimeIntervalMonthly:
+		return "monthly"
 	}
 	return "unknown"
 }
 
+// SortOrder specifies the order in which time series values are returned.
+// For valid options, see the SortOrder* package constants.
+type SortOrder uint8
+
+const (
+	// SortOrderAscending returns values from past to present
+	SortOrderAscending SortOrder = iota
+	// SortOrderDescending returns values from present to past
+	SortOrderDescending
+)
+
+func (o SortOrder) String() string {
+	switch o {
+	case SortOrderAscending:
+		return "SortOrderAscending"
+	case SortOrderDescending:
+		return "SortOrderDescending"
+	}
+	return "SortOrderUnknown"
+}
+
+// OutputSize specifies the number of values returned by a time series.
+// For valid options, see the OutputSize* package constants.
+type OutputSize uint8
+
+const (
+	// OutputSizeCompact returns the latest 100 values
+	OutputSizeCompact OutputSize = iota
+	// OutputSizeFull returns the full history
+	OutputSizeFull
+)
+
+func (s OutputSize) String() string {
+	switch s {
+	case OutputSizeCompact:
+		return "OutputSizeCompact"
+	case OutputSizeFull:
+		return "OutputSizeFull"
+	}
+	return "OutputSizeUnknown"
+}
+
+// keyName returns the name of the OutputSize used for Alpha Vantage API
+func (s OutputSize) keyName() string {
+	switch s {
+	case OutputSizeCompact:
+		return valueCompact
+	case OutputSizeFull:
+		return valueFull
+	}
+	return "unknown"
+}
+
+// sortTimeSeriesValues puts values sorted from past to present in the given order
+func sortTimeSeriesValues(values []*TimeSeriesValue, order SortOrder) []*TimeSeriesValue {
+	if order == SortOrderDescending {
+		for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+	return values
+}
+
+const (
+	// compactOutputSize is the number of values returned with the compact output size
+	compactOutputSize = 100
+	// compactOutputMargin accounts for market holidays when estimating the number of trading days
+	compactOutputMargin = 5
+)
+
+// OutputSizeSince returns the output size needed for a time series to reach back from now to since.
+// The compact output covers the latest 100 values, i.e. roughly 100 trading days of a daily series,
+// so older values need the full output. StockTimeSeriesRange queries this output size by default.
+func OutputSizeSince(timeSeries TimeSeries, since time.Time, now time.Time) OutputSize {
+	days := int(now.Sub(since).Hours() / 24)
+
+	var periods int
+	switch timeSeries {
+	case TimeSeriesWeekly, TimeSeriesWeeklyAdjusted:
+		periods = days / 7
+	case TimeSeriesMonthly, TimeSeriesMonthlyAdjusted:
+		periods = (now.Year()-since.Year())*12 + int(now.Month()-since.Month())
+	default:
+		// only weekdays are trading days
+		periods = days / 7 * 5
+		for d := since.AddDate(0, 0, days/7*7); d.Before(now); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+				periods++
+			}
+		}
+		periods += compactOutputMargin
+	}
+
+	if periods >= compactOutputSize {
+		return OutputSizeFull
+	}
+	return OutputSizeCompact
+}
+
+// validIntradayMonth reports whether the month of the year is a calendar month not after now
+func validIntradayMonth(year int, month int, now time.Time) bool {
+	if month < 1 || month > 12 {
+		return false
+	}
+	return year < now.Year() || (year == now.Year() && month <= int(now.Month()))
+}
+
+// filterTimeSeriesValues returns the values within the inclusive range from to
+func filterTimeSeriesValues(values []*TimeSeriesValue, from, to time.Time) []*TimeSeriesValue {
+	filtered := make([]*TimeSeriesValue, 0, len(values))
+	for _, value := range values {
+		if value.Time.Before(from) || value.Time.After(to) {
+			continue
+		}
+		filtered = append(filtered, value)
+	}
+	return filtered
+}
+
 var (
 	// timeSeriesDateFormats are the expected date formats in time series data
 	timeSeriesDateFormats = []string{
 		"2006-01-02",
 		"2006-01-02 15:04:05",
+		time.RFC3339,
 	}
 )
 
@@ -125,6 +303,16 @@ type TimeSeriesValue struct {
 	Low    float64
 	Close  float64
 	Volume float64
+
+	// RawClose is the close price exactly as returned by Alpha Vantage,
+	// for callers who cannot afford the rounding of a float64
+	RawClose string
+
+	// AdjustedClose, DividendAmount and SplitCoefficient are only set for adjusted time series,
+	// SplitCoefficient only for TimeSeriesDailyAdjusted
+	AdjustedClose    float64
+	DividendAmount   float64
+	SplitCoefficient float64
 }
 
 // sortTimeSeriesValuesByDate allows TimeSeriesValue
@@ -135,8 +323,25 @@ func (b sortTimeSeriesValuesByDate) Len() int           { return len(b) }
 func (b sortTimeSeriesValuesByDate) Less(i, j int) bool { return b[i].Time.Before(b[j].Time) }
 func (b sortTimeSeriesValuesByDate) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
 
-// parseTimeSeriesData will parse csv data from a reader
+// parseTimeSeriesData will parse csv data from a reader.
+// The first invalid row fails parsing with a *RowError.
+// ErrNoData is returned if the data is empty or only has a header.
 func parseTimeSeriesData(r io.Reader) ([]*TimeSeriesValue, error) {
+	values, _, err := parseTimeSeriesRows(r, true, parseOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, ErrNoData
+	}
+	return values, nil
+}
+
+// parseTimeSeriesRows will parse csv data from a reader.
+// Invalid rows fail parsing with a *RowError if strict is set,
+// otherwise they are skipped and returned.
+// Values failing validation are handled as configured in the parse options.
+func parseTimeSeriesRows(r io.Reader, strict bool, popts parseOptions) ([]*TimeSeriesValue, []*RowError, error) {
 
 	reader := csv.NewReader(r)
 	reader.ReuseRecord = true // optimization
@@ -144,27 +349,46 @@ func parseTimeSeriesData(r io.Reader) ([]*TimeSeriesValue, error) {
 	reader.TrailingComma = true
 	reader.TrimLeadingSpace = true
 
-	// strip header
-	if _, err := reader.Read(); err != nil {
+	header, err := reader.Read()
+	if err != nil {
 		if err == io.EOF {
-			return nil, nil
+			return nil, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
+	}
+	columns, err := newTimeSeriesColumns(header)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	values := make([]*TimeSeriesValue, 0, 64)
+	var invalid []*RowError
 
-	for {
+	for row := 1; ; row++ {
 		record, err := reader.Read()
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, err
+			return nil, nil, err
 		}
-		value, err := parseTimeSeriesRecord(record)
-		if err != nil {
-			return nil, err
+		value, rowErr := parseTimeSeriesRecord(record, columns)
+		if rowErr != nil {
+			rowErr.Row = row
+			if strict {
+				return nil, nil, rowErr
+			}
+			invalid = append(invalid, rowErr)
+			continue
+		}
+		if popts.invalid != invalidValuesKeep {
+			if rowErr := value.validate(); rowErr != nil {
+				rowErr.Row = row
+				invalid = append(invalid, rowErr)
+				if popts.invalid == invalidValuesDrop {
+					continue
+				}
+			}
 		}
 		values = append(values, value)
 	}
@@ -172,59 +396,150 @@ func parseTimeSeriesData(r io.Reader) ([]*TimeSeriesValue, error) {
 	// sort values by date
 	sort.Sort(sortTimeSeriesValuesByDate(values))
 
-	return values, nil
+	return values, invalid, nil
 
 }
 
-// parseDigitalCurrencySeriesRecord will parse an individual csv record
-func parseTimeSeriesRecord(s []string) (*TimeSeriesValue, error) {
-	// these are the expected columns in the csv record
-	const (
-		timestamp = iota
-		open
-		high
-		low
-		close
-		volume
-	)
+// Validate checks the prices of the value are consistent, that is the low price is not above
+// the high price, the open and close prices are within them and the volume is not negative.
+// The returned error describes the first inconsistency, its cause is ErrInvalidValue.
+func (v *TimeSeriesValue) Validate() error {
+	if rowErr := v.validate(); rowErr != nil {
+		return rowErr.Err
+	}
+	return nil
+}
 
-	value := &TimeSeriesValue{}
+// validate checks the value is consistent, see Validate.
+// The returned *RowError holds the inconsistent column, without a row.
+func (v *TimeSeriesValue) validate() *RowError {
+	invalid := func(column string, value float64, format string, args ...interface{}) *RowError {
+		return &RowError{
+			Column: column,
+			Value:  formatFloat(value),
+			Err:    errors.Wrapf(ErrInvalidValue, format, args...),
+		}
+	}
 
-	d, err := parseDate(s[timestamp], timeSeriesDateFormats...)
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing timestamp %s", s[timestamp])
+	switch {
+	case v.Low > v.High:
+		return invalid("low", v.Low, "low %v is above high %v", v.Low, v.High)
+	case v.Open < v.Low || v.Open > v.High:
+		return invalid("open", v.Open, "open %v is outside low %v and high %v", v.Open, v.Low, v.High)
+	case v.Close < v.Low || v.Close > v.High:
+		return invalid("close", v.Close, "close %v is outside low %v and high %v", v.Close, v.Low, v.High)
+	case v.Volume < 0:
+		return invalid("volume", v.Volume, "volume %v is negative", v.Volume)
 	}
-	value.Time = d
+	return nil
+}
 
-	f, err := parseFloat(s[open])
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing open %s", s[open])
+// timeSeriesColumns are the indexes of the value columns in a time series csv record.
+// Columns missing from the header are -1.
+type timeSeriesColumns struct {
+	open             int
+	high             int
+	low              int
+	close            int
+	volume           int
+	adjustedClose    int
+	dividendAmount   int
+	splitCoefficient int
+}
+
+// timeSeriesColumnName normalizes the name of a time series column or json field,
+// i.e. "adjusted close" and "adjusted_close" are both "adjusted_close".
+func timeSeriesColumnName(name string) string {
+	return strings.Replace(strings.ToLower(strings.TrimSpace(name)), " ", "_", -1)
+}
+
+// newTimeSeriesColumns maps the columns of a time series csv header by name.
+// Adjusted series name their columns with underscores (daily) or spaces (weekly, monthly),
+// i.e. "adjusted_close" or "adjusted close". The first column is always the timestamp.
+func newTimeSeriesColumns(header []string) (*timeSeriesColumns, error) {
+	columns := &timeSeriesColumns{-1, -1, -1, -1, -1, -1, -1, -1}
+	for i := 1; i < len(header); i++ {
+		switch timeSeriesColumnName(header[i]) {
+		case "open":
+			columns.open = i
+		case "high":
+			columns.high = i
+		case "low":
+			columns.low = i
+		case "close":
+			columns.close = i
+		case "volume":
+			columns.volume = i
+		case "adjusted_close":
+			columns.adjustedClose = i
+		case "dividend_amount":
+			columns.dividendAmount = i
+		case "split_coefficient":
+			columns.splitCoefficient = i
+		}
 	}
-	value.Open = f
 
-	f, err = parseFloat(s[high])
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing high %s", s[high])
+	required := []struct {
+		index int
+		name  string
+	}{
+		{columns.open, "open"},
+		{columns.high, "high"},
+		{columns.low, "low"},
+		{columns.close, "close"},
+		{columns.volume, "volume"},
+	}
+	for _, column := range required {
+		if column.index < 0 {
+			return nil, errors.Errorf("missing time series column %s", column.name)
+		}
 	}
-	value.High = f
+	return columns, nil
+}
+
+// parseTimeSeriesRecord will parse an individual csv record
+func parseTimeSeriesRecord(s []string, columns *timeSeriesColumns) (*TimeSeriesValue, *RowError) {
+	// the timestamp is always the first column
+	const timestamp = 0
+
+	value := &TimeSeriesValue{}
 
-	f, err = parseFloat(s[low])
+	d, err := parseDate(s[timestamp], timeSeriesDateFormats...)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing low %s", s[low])
+		return nil, &RowError{Column: "timestamp", Value: s[timestamp], Err: err}
 	}
-	value.Low = f
+	value.Time = d
 
-	f, err = parseFloat(s[close])
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing close %s", s[close])
+	// floats are parsed in column order, stopping at the first error.
+	// Missing optional columns are left at zero.
+	var rowErr *RowError
+	parse := func(i int, column string) float64 {
+		if rowErr != nil || i < 0 {
+			return 0
+		}
+		if i >= len(s) {
+			rowErr = &RowError{Column: column, Err: errMissingValue}
+			return 0
+		}
+		f, err := parseFloat(s[i])
+		if err != nil {
+			rowErr = &RowError{Column: column, Value: s[i], Err: err}
+		}
+		return f
 	}
-	value.Close = f
 
-	f, err = parseFloat(s[volume])
-	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing volume %s", s[volume])
+	value.Open = parse(columns.open, "open")
+	value.High = parse(columns.high, "high")
+	value.Low = parse(columns.low, "low")
+	value.Close = parse(columns.close, "close")
+	value.AdjustedClose = parse(columns.adjustedClose, "adjusted_close")
+	value.Volume = parse(columns.volume, "volume")
+	value.DividendAmount = parse(columns.dividendAmount, "dividend_amount")
+	value.SplitCoefficient = parse(columns.splitCoefficient, "split_coefficient")
+	if rowErr != nil {
+		return nil, rowErr
 	}
-	value.Volume = f
+	value.RawClose = s[columns.close]
 
 	return value, nil
 }
@@ -0,0 +1,80 @@
+package av
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	timeSeriesCSVHeader         = "timestamp,open,high,low,close,volume\n"
+	timeSeriesAdjustedCSVHeader = "timestamp,open,high,low,close,adjusted_close,volume,dividend_amount,split_coefficient\n"
+)
+
+// ParseTimeSeriesCSV parses time series values from csv with a header row,
+// as returned by Alpha Vantage or written by WriteTimeSeriesCSV.
+// If strict is set, the first invalid row fails parsing with a *RowError.
+// Otherwise invalid rows are skipped and returned with the valid values.
+// Values can also be validated with ParseOptions, i.e. WithDropInvalidValues.
+// Values are returned from past to present.
+func ParseTimeSeriesCSV(r io.Reader, strict bool, opts ...ParseOption) ([]*TimeSeriesValue, []*RowError, error) {
+	var popts parseOptions
+	for _, opt := range opts {
+		opt.apply(&popts)
+	}
+	return parseTimeSeriesRows(r, strict, popts)
+}
+
+// WriteTimeSeriesCSV writes values as csv with a header row, in the column order
+// returned by Alpha Vantage. Timestamps are formatted in RFC3339.
+// The close price is written as RawClose if it is set.
+// The adjusted close, dividend amount and split coefficient columns
+// are written if includeAdjusted is set.
+func WriteTimeSeriesCSV(w io.Writer, values []*TimeSeriesValue, includeAdjusted bool) error {
+	bw := bufio.NewWriter(w)
+
+	header := timeSeriesCSVHeader
+	if includeAdjusted {
+		header = timeSeriesAdjustedCSVHeader
+	}
+	if _, err := bw.WriteString(header); err != nil {
+		return err
+	}
+
+	// the line buffer is reused for every value
+	line := make([]byte, 0, 128)
+	for _, value := range values {
+		line = value.Time.AppendFormat(line[:0], time.RFC3339)
+		line = appendCSVFloat(line, value.Open)
+		line = appendCSVFloat(line, value.High)
+		line = appendCSVFloat(line, value.Low)
+		if value.RawClose != "" {
+			line = append(line, ',')
+			line = append(line, value.RawClose...)
+		} else {
+			line = appendCSVFloat(line, value.Close)
+		}
+		if includeAdjusted {
+			line = appendCSVFloat(line, value.AdjustedClose)
+		}
+		line = appendCSVFloat(line, value.Volume)
+		if includeAdjusted {
+			line = appendCSVFloat(line, value.DividendAmount)
+			line = appendCSVFloat(line, value.SplitCoefficient)
+		}
+		line = append(line, '\n')
+
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// appendCSVFloat appends a separator and the float value to the line
+func appendCSVFloat(line []byte, f float64) []byte {
+	line = append(line, ',')
+	return strconv.AppendFloat(line, f, 'f', -1, 64)
+}
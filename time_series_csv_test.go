@@ -0,0 +1,199 @@
+package av
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestWriteTimeSeriesCSV(t *testing.T) {
+	tests := []struct {
+		desc            string
+		data            string
+		includeAdjusted bool
+		header          string
+	}{
+		{
+			desc:   "time series",
+			data:   sampleTimeSeriesData,
+			header: "timestamp,open,high,low,close,volume",
+		},
+		{
+			desc:            "adjusted time series",
+			data:            sampleTimeSeriesAdjustedData,
+			includeAdjusted: true,
+			header:          "timestamp,open,high,low,close,adjusted_close,volume,dividend_amount,split_coefficient",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			values, err := parseTimeSeriesData(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := WriteTimeSeriesCSV(buf, values, tt.includeAdjusted); err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if header := strings.SplitN(buf.String(), "\n", 2)[0]; header != tt.header {
+				t.Errorf("unexpected header, want %s got %s", tt.header, header)
+			}
+
+			roundTrip, err := parseTimeSeriesData(buf)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if !reflect.DeepEqual(values, roundTrip) {
+				t.Errorf("values changed in round trip, want %v got %v", values, roundTrip)
+			}
+		})
+	}
+}
+
+func TestParseTimeSeriesData_adjusted(t *testing.T) {
+	values, err := parseTimeSeriesData(strings.NewReader(sampleTimeSeriesAdjustedData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(values))
+	}
+
+	first := values[0]
+	if first.AdjustedClose != 188.62 || first.Volume != 45155216 || first.DividendAmount != 0.24 || first.SplitCoefficient != 1 {
+		t.Errorf("unexpected adjusted value, got %+v", first)
+	}
+}
+
+func TestParseTimeSeriesData_weeklyAdjusted(t *testing.T) {
+	values, err := parseTimeSeriesData(strings.NewReader(sampleTimeSeriesWeeklyAdjustedData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(values))
+	}
+
+	expected := &TimeSeriesValue{
+		Time:           time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC),
+		Open:           187.15,
+		High:           191.05,
+		Low:            185.84,
+		Close:          188.85,
+		RawClose:       "188.8500",
+		Volume:         256101829,
+		AdjustedClose:  188.62,
+		DividendAmount: 0.24,
+	}
+	if !reflect.DeepEqual(values[0], expected) {
+		t.Errorf("unexpected weekly adjusted value, want %+v got %+v", expected, values[0])
+	}
+}
+
+func TestParseTimeSeriesData_unadjusted(t *testing.T) {
+	values, err := parseTimeSeriesData(strings.NewReader(sampleTimeSeriesData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	expected := &TimeSeriesValue{
+		Time:     time.Date(2018, 1, 4, 0, 0, 0, 0, time.UTC),
+		Open:     1097.09,
+		High:     1104.08,
+		Low:      1094.26,
+		Close:    1095.76,
+		Volume:   1289293,
+		RawClose: "1095.7600",
+	}
+	if last := values[len(values)-1]; !reflect.DeepEqual(last, expected) {
+		t.Errorf("unexpected value, want %+v got %+v", expected, last)
+	}
+}
+
+func TestParseTimeSeriesData_missingColumn(t *testing.T) {
+	_, err := parseTimeSeriesData(strings.NewReader("timestamp,open,high,low,close\n2018-01-04,1,2,1,2"))
+	if err == nil || !strings.Contains(err.Error(), "volume") {
+		t.Errorf("unexpected error, got %v", err)
+	}
+}
+
+func TestParseTimeSeriesCSV_invalidRow(t *testing.T) {
+	_, _, err := ParseTimeSeriesCSV(strings.NewReader(sampleTimeSeriesInvalidData), true)
+	rowErr, ok := err.(*RowError)
+	if !ok {
+		t.Fatalf("unexpected error, want *RowError got %v", err)
+	}
+	if rowErr.Row != 2 || rowErr.Column != "high" || rowErr.Value != "N/A" {
+		t.Errorf("unexpected row error, got %+v", rowErr)
+	}
+	if !strings.Contains(rowErr.Error(), "row 2") || !strings.Contains(rowErr.Error(), "high") {
+		t.Errorf("row and column are missing from the message, got %s", rowErr)
+	}
+
+	values, invalid, err := ParseTimeSeriesCSV(strings.NewReader(sampleTimeSeriesInvalidData), false)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected result count, want 2 got %d", len(values))
+	}
+	if len(invalid) != 1 || invalid[0].Row != 2 {
+		t.Errorf("unexpected invalid rows, got %v", invalid)
+	}
+}
+
+func TestParseTimeSeriesCSV_invalidValues(t *testing.T) {
+	tests := []struct {
+		desc  string
+		opts  []ParseOption
+		count int
+		flags int
+	}{
+		{desc: "keep", count: 3},
+		{desc: "drop", opts: []ParseOption{WithDropInvalidValues()}, count: 2, flags: 1},
+		{desc: "flag", opts: []ParseOption{WithFlagInvalidValues()}, count: 3, flags: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			values, invalid, err := ParseTimeSeriesCSV(strings.NewReader(sampleTimeSeriesInconsistentData), true, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if len(values) != tt.count {
+				t.Errorf("unexpected result count, want %d got %d", tt.count, len(values))
+			}
+			if len(invalid) != tt.flags {
+				t.Fatalf("unexpected invalid rows, got %v", invalid)
+			}
+			if tt.flags > 0 && (invalid[0].Row != 3 || invalid[0].Column != "close" || errors.Cause(invalid[0]) != ErrInvalidValue) {
+				t.Errorf("unexpected row error, got %+v", invalid[0])
+			}
+		})
+	}
+}
+
+func TestWriteTimeSeriesCSV_rawClose(t *testing.T) {
+	values, err := parseTimeSeriesData(strings.NewReader(sampleTimeSeriesAdjustedData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if last := values[len(values)-1]; last.RawClose != "170.7300" {
+		t.Errorf("unexpected raw close, want 170.7300 got %s", last.RawClose)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteTimeSeriesCSV(buf, values, false); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	const expected = "2024-03-08T00:00:00Z,169,173.7,168.94,170.7300,76114634"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("raw close not written, want %s in %s", expected, buf.String())
+	}
+}
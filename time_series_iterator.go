@@ -0,0 +1,101 @@
+package av
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// TimeSeriesIterator reads a time series one value at a time.
+//
+// Unlike StockTimeSeries, values are not buffered or sorted: they are returned
+// in the order Alpha Vantage sends them, which is from present to past.
+//
+// Usage
+//
+//	iter, err := client.StockTimeSeriesStream(ctx, av.TimeSeriesDailyAdjusted, "GOOGL")
+//	if err != nil {
+//		return err
+//	}
+//	defer iter.Close()
+//	for {
+//		value, err := iter.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		store(value)
+//	}
+type TimeSeriesIterator struct {
+	body    io.ReadCloser
+	reader  *csv.Reader
+	columns *timeSeriesColumns
+	closed  bool
+
+	// row is the number of rows read after the header
+	row int
+}
+
+// newTimeSeriesIterator creates a TimeSeriesIterator reading csv data from body
+func newTimeSeriesIterator(body io.ReadCloser) (*TimeSeriesIterator, error) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true // optimization
+	reader.LazyQuotes = true
+	reader.TrailingComma = true
+	reader.TrimLeadingSpace = true
+
+	iter := &TimeSeriesIterator{
+		body:   body,
+		reader: reader,
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		iter.Close()
+		if err == io.EOF {
+			return iter, nil
+		}
+		return nil, err
+	}
+	if iter.columns, err = newTimeSeriesColumns(header); err != nil {
+		iter.Close()
+		return nil, err
+	}
+
+	return iter, nil
+}
+
+// Next returns the next value of the time series.
+// io.EOF is returned once all values have been read, at which point
+// the underlying response body is closed.
+func (it *TimeSeriesIterator) Next() (*TimeSeriesValue, error) {
+	if it.closed {
+		return nil, io.EOF
+	}
+
+	record, err := it.reader.Read()
+	if err != nil {
+		it.Close()
+		return nil, err
+	}
+
+	it.row++
+	value, rowErr := parseTimeSeriesRecord(record, it.columns)
+	if rowErr != nil {
+		rowErr.Row = it.row
+		it.Close()
+		return nil, rowErr
+	}
+	return value, nil
+}
+
+// Close closes the underlying response body.
+// It is safe to call Close multiple times.
+func (it *TimeSeriesIterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	return it.body.Close()
+}
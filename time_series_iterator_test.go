@@ -0,0 +1,86 @@
+package av
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// closeCounter counts the number of times a body is closed
+type closeCounter struct {
+	*ResetBuffer
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestClient_StockTimeSeriesStream(t *testing.T) {
+	body := &closeCounter{ResetBuffer: NewBuffCloser(sampleTimeSeriesData)}
+	res := &http.Response{
+		Body:       body,
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	iter, err := client.StockTimeSeriesStream(context.Background(), TimeSeriesDaily, "TEST")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	expected, err := parseTimeSeriesData(NewBuffCloser(sampleTimeSeriesData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	count := 0
+	for {
+		value, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+		// streamed values are from present to past
+		if want := expected[len(expected)-1-count]; *value != *want {
+			t.Errorf("unexpected value, want %+v got %+v", want, value)
+		}
+		count++
+	}
+
+	if count != len(expected) {
+		t.Errorf("unexpected value count, want %d got %d", len(expected), count)
+	}
+	if body.closed != 1 {
+		t.Errorf("body was not closed once at the end, got %d", body.closed)
+	}
+	if err := iter.Close(); err != nil || body.closed != 1 {
+		t.Errorf("closing a finished iterator should be a no-op, got %v", err)
+	}
+}
+
+func TestTimeSeriesIterator_Close(t *testing.T) {
+	body := &closeCounter{ResetBuffer: NewBuffCloser(sampleTimeSeriesData)}
+	iter, err := newTimeSeriesIterator(body)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if _, err := iter.Next(); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if err := iter.Close(); err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if body.closed != 1 {
+		t.Errorf("body was not closed, got %d", body.closed)
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Errorf("unexpected error after close, want %v got %v", io.EOF, err)
+	}
+}
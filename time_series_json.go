@@ -0,0 +1,152 @@
+package av
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// jsonMetaDataKey is the key of the meta data of a json time series
+	jsonMetaDataKey = "Meta Data"
+)
+
+// SeriesMetadata describes a time series queried as json, see WithSeriesMetadata
+type SeriesMetadata struct {
+	Information   string
+	Symbol        string
+	LastRefreshed time.Time
+	// Interval is only set for intraday time series, i.e. "5min"
+	Interval   string
+	OutputSize string
+	TimeZone   string
+}
+
+// jsonFieldName strips the number Alpha Vantage prefixes json fields with,
+// i.e. "4. close" is "close"
+func jsonFieldName(key string) string {
+	if i := strings.Index(key, ". "); i >= 0 {
+		return key[i+2:]
+	}
+	return key
+}
+
+// parseTimeSeriesJSON will parse json data from a reader.
+// The meta data is decoded into md, unless it is nil.
+// ErrNoData is returned if the data has no values.
+func parseTimeSeriesJSON(r io.Reader, md *SeriesMetadata) ([]*TimeSeriesValue, error) {
+	var data map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "error decoding time series")
+	}
+
+	var series map[string]map[string]string
+	for key, raw := range data {
+		if key == jsonMetaDataKey {
+			if md == nil {
+				continue
+			}
+			if err := parseSeriesMetadata(raw, md); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// the other key names the series, i.e. "Time Series (Daily)"
+		if err := json.Unmarshal(raw, &series); err != nil {
+			return nil, errors.Wrapf(err, "error decoding %s", key)
+		}
+	}
+	if len(series) == 0 {
+		return nil, ErrNoData
+	}
+
+	values := make([]*TimeSeriesValue, 0, len(series))
+	for date, fields := range series {
+		value, err := parseTimeSeriesFields(date, fields)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	// sort values by date
+	sort.Sort(sortTimeSeriesValuesByDate(values))
+
+	return values, nil
+}
+
+// parseSeriesMetadata decodes the json meta data of a time series
+func parseSeriesMetadata(raw json.RawMessage, md *SeriesMetadata) error {
+	var fields map[string]string
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return errors.Wrap(err, "error decoding meta data")
+	}
+	for key, value := range fields {
+		switch strings.ToLower(jsonFieldName(key)) {
+		case "information":
+			md.Information = value
+		case "symbol":
+			md.Symbol = value
+		case "last refreshed":
+			d, err := parseDate(value, timeSeriesDateFormats...)
+			if err != nil {
+				return errors.Wrapf(err, "error parsing last refreshed %s", value)
+			}
+			md.LastRefreshed = d
+		case "interval":
+			md.Interval = value
+		case "output size":
+			md.OutputSize = value
+		case "time zone":
+			md.TimeZone = value
+		}
+	}
+	return nil
+}
+
+// parseTimeSeriesFields will parse the json fields of an individual value
+func parseTimeSeriesFields(date string, fields map[string]string) (*TimeSeriesValue, error) {
+	value := &TimeSeriesValue{}
+
+	d, err := parseDate(date, timeSeriesDateFormats...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing timestamp %s", date)
+	}
+	value.Time = d
+
+	for key, field := range fields {
+		var target *float64
+		switch timeSeriesColumnName(jsonFieldName(key)) {
+		case "open":
+			target = &value.Open
+		case "high":
+			target = &value.High
+		case "low":
+			target = &value.Low
+		case "close":
+			target = &value.Close
+			value.RawClose = field
+		case "volume":
+			target = &value.Volume
+		case "adjusted_close":
+			target = &value.AdjustedClose
+		case "dividend_amount":
+			target = &value.DividendAmount
+		case "split_coefficient":
+			target = &value.SplitCoefficient
+		default:
+			continue
+		}
+		f, err := parseFloat(field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing %s %s", key, field)
+		}
+		*target = f
+	}
+
+	return value, nil
+}
@@ -0,0 +1,65 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_StockTimeSeries_json(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=json&function=TIME_SERIES_DAILY_ADJUSTED&outputsize=compact&symbol=AAPL"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesAdjustedJSONData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	var md SeriesMetadata
+	values, err := client.StockTimeSeries(context.Background(), TimeSeriesDailyAdjusted, "AAPL", WithSeriesMetadata(&md))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+
+	// both data types yield the same values
+	csvValues, err := parseTimeSeriesData(NewBuffCloser(sampleTimeSeriesAdjustedData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if !reflect.DeepEqual(values, csvValues) {
+		t.Errorf("unexpected json values, want %v got %v", csvValues, values)
+	}
+
+	expectedMetadata := SeriesMetadata{
+		Information:   "Daily Time Series with Splits and Dividend Events",
+		Symbol:        "AAPL",
+		LastRefreshed: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+		OutputSize:    "Compact",
+		TimeZone:      "US/Eastern",
+	}
+	if md != expectedMetadata {
+		t.Errorf("unexpected meta data, want %+v got %+v", expectedMetadata, md)
+	}
+}
+
+func TestWithJSON_inapplicable(t *testing.T) {
+	conn := NewResponseConnection(&http.Response{})
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	if _, err := client.DigitalCurrency(context.Background(), "BTC", "USD", WithJSON()); err != ErrTimeSeriesOnly {
+		t.Errorf("unexpected error, want %v got %v", ErrTimeSeriesOnly, err)
+	}
+	if _, err := client.StockTimeSeriesStream(context.Background(), TimeSeriesDaily, "TEST", WithJSON()); err != ErrStreamJSON {
+		t.Errorf("unexpected error, want %v got %v", ErrStreamJSON, err)
+	}
+	if conn.endpoint != nil {
+		t.Errorf("request was made to %s", conn.endpoint)
+	}
+}
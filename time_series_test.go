@@ -1,7 +1,12 @@
 package av
 
 import (
+	"context"
+	"net/http"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func BenchmarkParseTimeSeriesData(b *testing.B) {
@@ -13,3 +18,503 @@ func BenchmarkParseTimeSeriesData(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkParseTimeSeriesData_full(b *testing.B) {
+	buff := NewBuffCloser(generateTimeSeriesData(5000))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buff.Restart()
+		if _, err := parseTimeSeriesData(buff); err != nil {
+			b.Fatalf("error parsing series: %v", err)
+		}
+	}
+}
+
+func TestClient_StockTimeSeriesFull(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(generateTimeSeriesData(5000)),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	values, err := client.StockTimeSeriesFull(context.Background(), TimeSeriesDaily, "TEST")
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	expected := "query?apikey=test&datatype=csv&function=TIME_SERIES_DAILY&outputsize=full&symbol=TEST"
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(values) != 5000 {
+		t.Errorf("unexpected result count, want 5000 got %d", len(values))
+	}
+
+	_, _ = client.StockTimeSeriesIntradayFull(context.Background(), TimeIntervalOneMinute, "TEST")
+	expected = "query?apikey=test&datatype=csv&function=TIME_SERIES_INTRADAY&interval=1min&outputsize=full&symbol=TEST"
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+}
+
+func TestClient_StockTimeSeriesRange(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	from := time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2017, 12, 29, 0, 0, 0, 0, time.UTC)
+	result, err := client.StockTimeSeriesRange(context.Background(), TimeSeriesDaily, "TEST", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	// the range is long ago, so the full output is required
+	if size := conn.endpoint.Query().Get(queryOutputSize); size != valueFull {
+		t.Errorf("unexpected output size, want %s got %s", valueFull, size)
+	}
+	if len(result) != 20 {
+		t.Fatalf("unexpected result count, want 20 got %d", len(result))
+	}
+	if !result[0].Time.Equal(from) || !result[len(result)-1].Time.Equal(to) {
+		t.Errorf("range is not inclusive, got %s to %s", result[0].Time, result[len(result)-1].Time)
+	}
+}
+
+func TestClient_StockTimeSeriesRange_outputSize(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	from := time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2017, 12, 29, 0, 0, 0, 0, time.UTC)
+	_, _ = client.StockTimeSeriesRange(context.Background(), TimeSeriesDaily, "TEST", from, to, WithCallOutputSize(OutputSizeCompact))
+
+	if size := conn.endpoint.Query().Get(queryOutputSize); size != valueCompact {
+		t.Errorf("unexpected output size, want %s got %s", valueCompact, size)
+	}
+}
+
+func TestOutputSizeSince(t *testing.T) {
+	now := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		desc       string
+		timeSeries TimeSeries
+		since      time.Time
+		expected   OutputSize
+	}{
+		{
+			desc:       "daily recent",
+			timeSeries: TimeSeriesDaily,
+			since:      now.AddDate(0, 0, -30),
+			expected:   OutputSizeCompact,
+		},
+		{
+			desc:       "daily old",
+			timeSeries: TimeSeriesDailyAdjusted,
+			since:      now.AddDate(-1, 0, 0),
+			expected:   OutputSizeFull,
+		},
+		{
+			// 94 trading days and the holiday margin stay below 100 values
+			desc:       "daily below boundary",
+			timeSeries: TimeSeriesDailyAdjusted,
+			since:      now.AddDate(0, 0, -132),
+			expected:   OutputSizeCompact,
+		},
+		{
+			// 95 trading days and the holiday margin reach 100 values
+			desc:       "daily at boundary",
+			timeSeries: TimeSeriesDailyAdjusted,
+			since:      now.AddDate(0, 0, -133),
+			expected:   OutputSizeFull,
+		},
+		{
+			desc:       "weekly recent",
+			timeSeries: TimeSeriesWeekly,
+			since:      now.AddDate(-1, 0, 0),
+			expected:   OutputSizeCompact,
+		},
+		{
+			desc:       "weekly old",
+			timeSeries: TimeSeriesWeeklyAdjusted,
+			since:      now.AddDate(-3, 0, 0),
+			expected:   OutputSizeFull,
+		},
+		{
+			desc:       "monthly recent",
+			timeSeries: TimeSeriesMonthly,
+			since:      now.AddDate(-5, 0, 0),
+			expected:   OutputSizeCompact,
+		},
+		{
+			desc:       "monthly old",
+			timeSeries: TimeSeriesMonthlyAdjusted,
+			since:      now.AddDate(-10, 0, 0),
+			expected:   OutputSizeFull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := OutputSizeSince(tt.timeSeries, tt.since, now); got != tt.expected {
+				t.Errorf("unexpected output size, want %s got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_StockTimeSeries_sortOrder(t *testing.T) {
+	tests := []struct {
+		order SortOrder
+		less  func(a, b time.Time) bool
+	}{
+		{
+			order: SortOrderAscending,
+			less:  time.Time.Before,
+		},
+		{
+			order: SortOrderDescending,
+			less:  time.Time.After,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleTimeSeriesData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn), WithSortOrder(tt.order))
+
+			result, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST")
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			for i := 1; i < len(result); i++ {
+				if !tt.less(result[i-1].Time, result[i].Time) {
+					t.Fatalf("unexpected order at %d: %s then %s", i, result[i-1].Time, result[i].Time)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeSeries_IsValid(t *testing.T) {
+	tests := []struct {
+		series TimeSeries
+		valid  bool
+	}{
+		{series: TimeSeriesDaily, valid: true},
+		{series: TimeSeriesMonthlyAdjusted, valid: true},
+		{series: TimeSeriesMonthlyAdjusted + 1, valid: false},
+		{series: TimeSeries(99), valid: false},
+	}
+
+	for _, tt := range tests {
+		if valid := tt.series.IsValid(); valid != tt.valid {
+			t.Errorf("unexpected validity of %d, want %v got %v", tt.series, tt.valid, valid)
+		}
+	}
+}
+
+func TestTimeInterval_IsValid(t *testing.T) {
+	tests := []struct {
+		interval TimeInterval
+		valid    bool
+	}{
+		{interval: TimeIntervalOneMinute, valid: true},
+		{interval: TimeIntervalMonthly, valid: true},
+		{interval: TimeIntervalMonthly + 1, valid: false},
+		{interval: TimeInterval(99), valid: false},
+	}
+
+	for _, tt := range tests {
+		if valid := tt.interval.IsValid(); valid != tt.valid {
+			t.Errorf("unexpected validity of %d, want %v got %v", tt.interval, tt.valid, valid)
+		}
+	}
+}
+
+func TestClient_invalidEnums(t *testing.T) {
+	tests := []struct {
+		desc     string
+		call     func(c *Client) error
+		expected error
+	}{
+		{
+			desc: "series",
+			call: func(c *Client) error {
+				_, err := c.StockTimeSeries(context.Background(), TimeSeriesMonthlyAdjusted+1, "TEST")
+				return err
+			},
+			expected: ErrInvalidSeries,
+		},
+		{
+			desc: "series stream",
+			call: func(c *Client) error {
+				_, err := c.StockTimeSeriesStream(context.Background(), TimeSeries(99), "TEST")
+				return err
+			},
+			expected: ErrInvalidSeries,
+		},
+		{
+			desc: "intraday interval",
+			call: func(c *Client) error {
+				_, err := c.StockTimeSeriesIntraday(context.Background(), TimeIntervalSixtyMinute+1, "TEST")
+				return err
+			},
+			expected: ErrInvalidInterval,
+		},
+		{
+			desc: "indicator interval",
+			call: func(c *Client) error {
+				_, err := c.RSI(context.Background(), "TEST", TimeIntervalMonthly+1, 14, SeriesTypeClose)
+				return err
+			},
+			expected: ErrInvalidInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			conn := NewErrorConnection(errors.New("unexpected request"))
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			if err := tt.call(client); err != tt.expected {
+				t.Errorf("unexpected error, want %v got %v", tt.expected, err)
+			}
+			if conn.endpoint != nil {
+				t.Errorf("request was made to %s", conn.endpoint)
+			}
+		})
+	}
+}
+
+func TestClient_StockTimeSeriesIntraday_month(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	_, _ = client.StockTimeSeriesIntraday(context.Background(), TimeIntervalFiveMinute, "TEST", WithIntradayMonth(2009, 1))
+	expected := "query?apikey=test&datatype=csv&function=TIME_SERIES_INTRADAY&interval=5min&month=2009-01&outputsize=full&symbol=TEST"
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+
+	// a compact output size would truncate the month
+	_, _ = client.StockTimeSeriesIntraday(context.Background(), TimeIntervalFiveMinute, "TEST", WithIntradayMonth(2009, 1), WithCallOutputSize(OutputSizeCompact))
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+
+	now := time.Now()
+	if _, err := client.StockTimeSeriesIntraday(context.Background(), TimeIntervalFiveMinute, "TEST", WithIntradayMonth(now.Year()+1, 1)); err != ErrInvalidMonth {
+		t.Errorf("unexpected error, want %v got %v", ErrInvalidMonth, err)
+	}
+	if _, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", WithIntradayMonth(2009, 1)); err != ErrIntradayOnly {
+		t.Errorf("unexpected error, want %v got %v", ErrIntradayOnly, err)
+	}
+}
+
+func TestValidIntradayMonth(t *testing.T) {
+	now := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		year     int
+		month    int
+		expected bool
+	}{
+		{2009, 1, true},
+		{2024, 3, true},
+		{2024, 4, false},
+		{2025, 1, false},
+		{2020, 0, false},
+		{2020, 13, false},
+	}
+
+	for _, tt := range tests {
+		if valid := validIntradayMonth(tt.year, tt.month, now); valid != tt.expected {
+			t.Errorf("unexpected validity of %04d-%02d, want %t got %t", tt.year, tt.month, tt.expected, valid)
+		}
+	}
+}
+
+func TestClient_StockTimeSeriesIntraday_extendedHours(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []RequestOption
+		expected string
+	}{
+		{
+			desc:     "default",
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_INTRADAY&interval=5min&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "extended",
+			opts:     []RequestOption{WithExtendedHours(true)},
+			expected: "query?apikey=test&datatype=csv&extended_hours=true&function=TIME_SERIES_INTRADAY&interval=5min&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "regular",
+			opts:     []RequestOption{WithExtendedHours(false)},
+			expected: "query?apikey=test&datatype=csv&extended_hours=false&function=TIME_SERIES_INTRADAY&interval=5min&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, _ = client.StockTimeSeriesIntraday(context.Background(), TimeIntervalFiveMinute, "TEST", tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+
+	if _, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", WithExtendedHours(false)); err != ErrIntradayOnly {
+		t.Errorf("unexpected error, want %v got %v", ErrIntradayOnly, err)
+	}
+}
+
+func TestTimeSeriesValue_Validate(t *testing.T) {
+	tests := []struct {
+		desc  string
+		value TimeSeriesValue
+		valid bool
+	}{
+		{desc: "valid", value: TimeSeriesValue{Open: 10, High: 12, Low: 9, Close: 11, Volume: 100}, valid: true},
+		{desc: "flat", value: TimeSeriesValue{Open: 10, High: 10, Low: 10, Close: 10}, valid: true},
+		{desc: "high below low", value: TimeSeriesValue{Open: 10, High: 9, Low: 12, Close: 11}},
+		{desc: "open below low", value: TimeSeriesValue{Open: 8, High: 12, Low: 9, Close: 11}},
+		{desc: "close above high", value: TimeSeriesValue{Open: 10, High: 12, Low: 9, Close: 13}},
+		{desc: "negative volume", value: TimeSeriesValue{Open: 10, High: 12, Low: 9, Close: 11, Volume: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.value.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error, got %v", err)
+			}
+			if !tt.valid && errors.Cause(err) != ErrInvalidValue {
+				t.Errorf("unexpected error, want %v got %v", ErrInvalidValue, err)
+			}
+		})
+	}
+}
+
+func TestClient_StockTimeSeriesIntraday_adjusted(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []RequestOption
+		expected string
+	}{
+		{
+			desc:     "default",
+			expected: "query?apikey=test&datatype=csv&function=TIME_SERIES_INTRADAY&interval=5min&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "adjusted",
+			opts:     []RequestOption{WithIntradayAdjusted(true)},
+			expected: "query?adjusted=true&apikey=test&datatype=csv&function=TIME_SERIES_INTRADAY&interval=5min&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc:     "raw",
+			opts:     []RequestOption{WithIntradayAdjusted(false)},
+			expected: "query?adjusted=false&apikey=test&datatype=csv&function=TIME_SERIES_INTRADAY&interval=5min&outputsize=compact&symbol=TEST",
+		},
+	}
+
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleTimeSeriesData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, _ = client.StockTimeSeriesIntraday(context.Background(), TimeIntervalFiveMinute, "TEST", tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+
+	// the option is rejected rather than ignored for other series
+	conn.endpoint = nil
+	if _, err := client.StockTimeSeries(context.Background(), TimeSeriesDailyAdjusted, "TEST", WithIntradayAdjusted(false)); err != ErrIntradayOnly {
+		t.Errorf("unexpected error, want %v got %v", ErrIntradayOnly, err)
+	}
+	if conn.endpoint != nil {
+		t.Errorf("request was made to %s", conn.endpoint)
+	}
+}
+
+func TestClient_StockTimeSeries_noData(t *testing.T) {
+	tests := []struct {
+		desc     string
+		data     string
+		opts     []RequestOption
+		expected error
+	}{
+		{desc: "empty", data: "", expected: ErrNoData},
+		{desc: "header only", data: sampleTimeSeriesHeaderOnlyData, expected: ErrNoData},
+		{desc: "empty allowed", data: "", opts: []RequestOption{WithAllowEmpty()}},
+		{desc: "header only allowed", data: sampleTimeSeriesHeaderOnlyData, opts: []RequestOption{WithAllowEmpty()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(tt.data),
+				StatusCode: http.StatusOK,
+			}
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(NewResponseConnection(res)))
+
+			values, err := client.StockTimeSeries(context.Background(), TimeSeriesDaily, "TEST", tt.opts...)
+			if err != tt.expected {
+				t.Fatalf("unexpected error, want %v got %v", tt.expected, err)
+			}
+			if tt.expected == nil && (values == nil || len(values) != 0) {
+				t.Errorf("unexpected values, want an empty slice got %v", values)
+			}
+		})
+	}
+}
+
+func TestParseTimeSeriesData_extendedHours(t *testing.T) {
+	values, err := parseTimeSeriesData(NewBuffCloser(sampleTimeSeriesExtendedHoursData))
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(values) != 4 {
+		t.Fatalf("unexpected result count, want 4 got %d", len(values))
+	}
+
+	first, last := values[0].Time, values[len(values)-1].Time
+	if expected := time.Date(2024, 3, 8, 4, 0, 0, 0, time.UTC); !first.Equal(expected) {
+		t.Errorf("unexpected pre market time, want %s got %s", expected, first)
+	}
+	if expected := time.Date(2024, 3, 8, 19, 55, 0, 0, time.UTC); !last.Equal(expected) {
+		t.Errorf("unexpected post market time, want %s got %s", expected, last)
+	}
+}
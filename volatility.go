@@ -0,0 +1,83 @@
+package av
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	queryNbDevUp = "nbdevup"
+	queryNbDevDn = "nbdevdn"
+	queryMAType  = "matype"
+
+	valueBBANDSEndpoint = "BBANDS"
+	valueATREndpoint    = "ATR"
+	valueNATREndpoint   = "NATR"
+	valueTRANGEEndpoint = "TRANGE"
+
+	columnUpperBand  = "Real Upper Band"
+	columnMiddleBand = "Real Middle Band"
+	columnLowerBand  = "Real Lower Band"
+)
+
+// BBANDSValue is a piece of data for a given time about the Bollinger bands
+type BBANDSValue struct {
+	Time   time.Time
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// BBANDS queries the Bollinger bands of a symbol.
+// The standard deviation multipliers of the upper and lower bands default to 2
+// and can be changed with WithNbDevUp and WithNbDevDn.
+// The moving average type defaults to MATypeSMA and can be changed with WithMAType.
+// Data is returned from past to present.
+func (c *Client) BBANDS(ctx context.Context, symbol string, interval TimeInterval, timePeriod int, seriesType SeriesType, opts ...IndicatorOption) ([]*BBANDSValue, error) {
+	params := indicatorParams(map[string]string{
+		queryNbDevUp: "2",
+		queryNbDevDn: "2",
+		queryMAType:  "0",
+	}, opts)
+	params[queryTimePeriod] = strconv.Itoa(timePeriod)
+	params[querySeriesType] = seriesType.keyName()
+
+	values, err := c.technicalIndicator(ctx, valueBBANDSEndpoint, symbol, interval, params)
+	if err != nil {
+		return nil, err
+	}
+
+	bbands := make([]*BBANDSValue, 0, len(values))
+	for _, value := range values {
+		v, err := value.lookup(columnUpperBand, columnMiddleBand, columnLowerBand)
+		if err != nil {
+			return nil, err
+		}
+		bbands = append(bbands, &BBANDSValue{
+			Time:   value.Time,
+			Upper:  v[0],
+			Middle: v[1],
+			Lower:  v[2],
+		})
+	}
+	return bbands, nil
+}
+
+// ATR queries the average true range of a symbol.
+// Data is returned from past to present.
+func (c *Client) ATR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueATREndpoint, symbol, interval, timePeriod)
+}
+
+// NATR queries the normalized average true range of a symbol.
+// Data is returned from past to present.
+func (c *Client) NATR(ctx context.Context, symbol string, interval TimeInterval, timePeriod int) ([]*IndicatorValue, error) {
+	return c.periodIndicator(ctx, valueNATREndpoint, symbol, interval, timePeriod)
+}
+
+// TRANGE queries the true range of a symbol.
+// Data is returned from past to present.
+func (c *Client) TRANGE(ctx context.Context, symbol string, interval TimeInterval) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueTRANGEEndpoint, symbol, interval, nil)
+}
@@ -0,0 +1,128 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_BBANDS_buildsUrl(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []IndicatorOption
+		expected string
+	}{
+		{
+			desc:     "defaults",
+			opts:     []IndicatorOption{WithNbDevUp(2), WithNbDevDn(2)},
+			expected: "query?apikey=test&datatype=csv&function=BBANDS&interval=daily&outputsize=compact&series_type=close&symbol=TEST&time_period=20",
+		},
+		{
+			desc:     "overrides",
+			opts:     []IndicatorOption{WithNbDevUp(3), WithNbDevDn(1), WithMAType(MATypeEMA)},
+			expected: "query?apikey=test&datatype=csv&function=BBANDS&interval=daily&matype=1&nbdevdn=1&nbdevup=3&outputsize=compact&series_type=close&symbol=TEST&time_period=20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleBBANDSData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			_, _ = client.BBANDS(context.Background(), "TEST", TimeIntervalDaily, 20, SeriesTypeClose, tt.opts...)
+
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+		})
+	}
+}
+
+func TestClient_BBANDS_getsResults(t *testing.T) {
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleBBANDSData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.BBANDS(context.Background(), "TEST", TimeIntervalDaily, 20, SeriesTypeClose)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	// the columns are mapped by name, not by position
+	last := result[len(result)-1]
+	if last.Upper != 418.0526 || last.Middle != 406.6230 || last.Lower != 395.1934 {
+		t.Errorf("unexpected last value, got %+v", last)
+	}
+}
+
+func TestClient_trueRange(t *testing.T) {
+	tests := []struct {
+		desc     string
+		call     func(c *Client) ([]*IndicatorValue, error)
+		data     string
+		expected string
+		last     float64
+	}{
+		{
+			desc: "ATR",
+			call: func(c *Client) ([]*IndicatorValue, error) {
+				return c.ATR(context.Background(), "TEST", TimeIntervalSixtyMinute, 14)
+			},
+			data:     sampleATRData,
+			expected: "query?apikey=test&datatype=csv&function=ATR&interval=60min&outputsize=compact&symbol=TEST&time_period=14",
+			last:     0.4871,
+		},
+		{
+			desc: "NATR",
+			call: func(c *Client) ([]*IndicatorValue, error) {
+				return c.NATR(context.Background(), "TEST", TimeIntervalDaily, 14)
+			},
+			data:     sampleNATRData,
+			expected: "query?apikey=test&datatype=csv&function=NATR&interval=daily&outputsize=compact&symbol=TEST&time_period=14",
+			last:     1.3122,
+		},
+		{
+			desc: "TRANGE",
+			call: func(c *Client) ([]*IndicatorValue, error) {
+				return c.TRANGE(context.Background(), "TEST", TimeIntervalDaily)
+			},
+			data:     sampleTRANGEData,
+			expected: "query?apikey=test&datatype=csv&function=TRANGE&interval=daily&outputsize=compact&symbol=TEST",
+			last:     4.76,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(tt.data),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := tt.call(client)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != tt.last {
+				t.Errorf("unexpected value, want %f got %f", tt.last, v)
+			}
+		})
+	}
+}
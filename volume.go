@@ -0,0 +1,45 @@
+package av
+
+import (
+	"context"
+)
+
+const (
+	valueOBVEndpoint   = "OBV"
+	valueADEndpoint    = "AD"
+	valueADOSCEndpoint = "ADOSC"
+	valueVWAPEndpoint  = "VWAP"
+)
+
+// OBV queries the on balance volume of a symbol.
+// Data is returned from past to present.
+func (c *Client) OBV(ctx context.Context, symbol string, interval TimeInterval) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueOBVEndpoint, symbol, interval, nil)
+}
+
+// ChaikinAD queries the Chaikin accumulation / distribution line of a symbol.
+// Data is returned from past to present.
+func (c *Client) ChaikinAD(ctx context.Context, symbol string, interval TimeInterval) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueADEndpoint, symbol, interval, nil)
+}
+
+// ChaikinADOsc queries the Chaikin accumulation / distribution oscillator of a symbol.
+// The fast and slow periods default to 3 and 10 and can be changed
+// with WithFastPeriod and WithSlowPeriod.
+// Data is returned from past to present.
+func (c *Client) ChaikinADOsc(ctx context.Context, symbol string, interval TimeInterval, opts ...IndicatorOption) ([]*IndicatorValue, error) {
+	return c.technicalIndicator(ctx, valueADOSCEndpoint, symbol, interval, indicatorParams(map[string]string{
+		queryFastPeriod: "3",
+		querySlowPeriod: "10",
+	}, opts))
+}
+
+// VWAP queries the volume weighted average price of a symbol.
+// Only intraday intervals are supported, ErrInvalidInterval is returned for any other interval.
+// Data is returned from past to present.
+func (c *Client) VWAP(ctx context.Context, symbol string, interval TimeInterval) ([]*IndicatorValue, error) {
+	if !interval.isIntraday() {
+		return nil, ErrInvalidInterval
+	}
+	return c.technicalIndicator(ctx, valueVWAPEndpoint, symbol, interval, nil)
+}
@@ -0,0 +1,136 @@
+package av
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_OBV(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=OBV&interval=daily&outputsize=compact&symbol=TEST"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleOBVLargeData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.OBV(context.Background(), "TEST", TimeIntervalDaily)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	// values are beyond the int32 range
+	if v := result[0].Value(); v != 98765432109 {
+		t.Errorf("unexpected value, want 98765432109 got %f", v)
+	}
+	if v := result[len(result)-1].Value(); v != -12345678901 {
+		t.Errorf("unexpected value, want -12345678901 got %f", v)
+	}
+}
+
+func TestClient_Chaikin(t *testing.T) {
+	tests := []struct {
+		desc     string
+		call     func(c *Client) ([]*IndicatorValue, error)
+		expected string
+	}{
+		{
+			desc: "AD",
+			call: func(c *Client) ([]*IndicatorValue, error) {
+				return c.ChaikinAD(context.Background(), "TEST", TimeIntervalDaily)
+			},
+			expected: "query?apikey=test&datatype=csv&function=AD&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc: "ADOSC defaults",
+			call: func(c *Client) ([]*IndicatorValue, error) {
+				return c.ChaikinADOsc(context.Background(), "TEST", TimeIntervalDaily, WithFastPeriod(3), WithSlowPeriod(10))
+			},
+			expected: "query?apikey=test&datatype=csv&function=ADOSC&interval=daily&outputsize=compact&symbol=TEST",
+		},
+		{
+			desc: "ADOSC overrides",
+			call: func(c *Client) ([]*IndicatorValue, error) {
+				return c.ChaikinADOsc(context.Background(), "TEST", TimeIntervalDaily, WithFastPeriod(5), WithSlowPeriod(20))
+			},
+			expected: "query?apikey=test&datatype=csv&fastperiod=5&function=ADOSC&interval=daily&outputsize=compact&slowperiod=20&symbol=TEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			res := &http.Response{
+				Body:       NewBuffCloser(sampleChaikinData),
+				StatusCode: http.StatusOK,
+			}
+			conn := NewResponseConnection(res)
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			result, err := tt.call(client)
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+			if conn.endpoint.String() != tt.expected {
+				t.Errorf("unexpected url, want %s got %s", tt.expected, conn.endpoint.String())
+			}
+			if len(result) != 3 {
+				t.Fatalf("unexpected result count, want 3 got %d", len(result))
+			}
+			if v := result[len(result)-1].Value(); v != 1234567.8901 {
+				t.Errorf("unexpected value, want 1234567.8901 got %f", v)
+			}
+		})
+	}
+}
+
+func TestClient_VWAP(t *testing.T) {
+	const (
+		expected = "query?apikey=test&datatype=csv&function=VWAP&interval=5min&outputsize=compact&symbol=TEST"
+	)
+	res := &http.Response{
+		Body:       NewBuffCloser(sampleVWAPData),
+		StatusCode: http.StatusOK,
+	}
+	conn := NewResponseConnection(res)
+	client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+	result, err := client.VWAP(context.Background(), "TEST", TimeIntervalFiveMinute)
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+	if conn.endpoint.String() != expected {
+		t.Errorf("unexpected url, want %s got %s", expected, conn.endpoint.String())
+	}
+	if len(result) != 3 {
+		t.Fatalf("unexpected result count, want 3 got %d", len(result))
+	}
+	for i := 1; i < len(result); i++ {
+		if !result[i-1].Time.Before(result[i].Time) {
+			t.Fatalf("results are not sorted from past to present")
+		}
+	}
+}
+
+func TestClient_VWAP_invalidInterval(t *testing.T) {
+	for _, interval := range []TimeInterval{TimeIntervalDaily, TimeIntervalWeekly, TimeIntervalMonthly} {
+		t.Run(interval.String(), func(t *testing.T) {
+			conn := NewResponseConnection(&http.Response{})
+			client := NewClient(WithAPIKey(testApiKey), WithConnection(conn))
+
+			if _, err := client.VWAP(context.Background(), "TEST", interval); err != ErrInvalidInterval {
+				t.Errorf("unexpected error, want %v got %v", ErrInvalidInterval, err)
+			}
+			if conn.endpoint != nil {
+				t.Errorf("request was made to %s", conn.endpoint)
+			}
+		})
+	}
+}